@@ -30,19 +30,101 @@ var (
 	PhaseInitialising StatusPhase = "initialising"
 )
 
+// Condition types of a Rocket
+const (
+	// ConditionReady is true if the database and the webserver are ready
+	ConditionReady = "Ready"
+	// ConditionDatabaseReady is true if all mongodb replicas are ready
+	ConditionDatabaseReady = "DatabaseReady"
+	// ConditionWebserverReady is true if all Rocket.Chat replicas are ready
+	ConditionWebserverReady = "WebserverReady"
+	// ConditionIngressReady is true if the ingress got an address assigned, it is absent if Rocket.Chat isn't exposed outside of the cluster
+	ConditionIngressReady = "IngressReady"
+	// ConditionDegraded is true if the last reconciliation failed
+	ConditionDegraded = "Degraded"
+	// ConditionProgressing is true while resources are created, updated or not ready yet
+	ConditionProgressing = "Progressing"
+	// ConditionFieldConflict is true if other field managers changed fields managed by the operator
+	ConditionFieldConflict = "FieldConflict"
+	// ConditionDatabaseUpgrading is true while a major upgrade of the database is in progress
+	ConditionDatabaseUpgrading = "DatabaseUpgrading"
+	// ConditionVersionsCompatible is false if the Rocket.Chat release doesn't support the version of the database
+	ConditionVersionsCompatible = "VersionsCompatible"
+	// ConditionWebserverUpgrading is true while a new version of Rocket.Chat waits for the backup taken before its rollout
+	ConditionWebserverUpgrading = "WebserverUpgrading"
+	// ConditionCertificateReady is true if the certificate issued for the ingress is ready and doesn't expire soon
+	ConditionCertificateReady = "CertificateReady"
+)
+
+// DatabaseUpgradePhase is the step of a major upgrade of the database
+type DatabaseUpgradePhase string
+
+const (
+	// DatabaseUpgradeRollingOut rolls the statefulSet to the image of the next major version
+	DatabaseUpgradeRollingOut DatabaseUpgradePhase = "RollingOut"
+	// DatabaseUpgradeWaitingForReplicaSet waits until all members of the replica set are healthy again
+	DatabaseUpgradeWaitingForReplicaSet DatabaseUpgradePhase = "WaitingForReplicaSet"
+	// DatabaseUpgradeSettingFeatureCompatibility runs the job raising the featureCompatibilityVersion to the new major version
+	DatabaseUpgradeSettingFeatureCompatibility DatabaseUpgradePhase = "SettingFeatureCompatibilityVersion"
+	// DatabaseUpgradeCompleted is set once the featureCompatibilityVersion matches the new major version
+	DatabaseUpgradeCompleted DatabaseUpgradePhase = "Completed"
+	// DatabaseUpgradeFailed is set if the replica set didn't become healthy in time or the job failed too often.
+	// The new version stays deployed until spec.database.version is changed.
+	DatabaseUpgradeFailed DatabaseUpgradePhase = "Failed"
+)
+
+// DeletionPolicy decides what happens to the data of the database when the Rocket is deleted
+// +kubebuilder:validation:Enum=Retain;Delete;Snapshot
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the persistent volume claims and the secrets of the database
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the persistent volume claims and the secrets of the database
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicySnapshot takes a VolumeSnapshot of every persistent volume claim before deleting it.
+	// The secrets are kept to access the data restored from the snapshots.
+	// Clusters without the volume snapshot API retain the persistent volume claims instead.
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 type RocketDatabase struct {
-	// Version of the Mongodb Containers, matches a Tag from https://hub.docker.com/r/bitnami/mongodb repository
+	// Version of the Mongodb Containers, matches a Tag from https://hub.docker.com/r/bitnami/mongodb repository.
+	// For an external database it is the version of the database, used to check its compatibility with Rocket.Chat.
 	// +optional
 	Version string `json:"version,omitempty"`
+	// External connects Rocket.Chat to an existing database, no mongodb replica set is deployed by the operator
+	// +optional
+	External *ExternalDatabase `json:"external,omitempty"`
+	// PodTemplate configures the resources and the scheduling of the mongodb pods
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
 	// Replicas of Mongodb Instance
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
-	// StorageSpec embedds a PersistentVolumeClaim Template
+	// StorageSpec embedds a PersistentVolumeClaim Template, defaults to a claim of 8Gi.
+	// It is ignored for an external database.
 	// (+)kubebuilder:validation:EmbeddedResource
+	// +optional
 	StorageSpec *EmbeddedPersistentVolumeClaim `json:"storageSpec,omitempty"`
+	// DeletionPolicy decides what happens to the persistent volume claims, the auth and the admin secret
+	// when the Rocket is deleted. Defaults to Retain.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// VolumeSnapshotClassName is the VolumeSnapshotClass used by the Snapshot deletion policy,
+	// the default class of the cluster is used if it is empty.
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// ExternalDatabase is a mongodb replica set which isn't managed by the operator
+type ExternalDatabase struct {
+	// SecretRef references the secret containing the connection strings of the database
+	// in the keys uri and oplog-uri
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
 }
 
 // RocketAdminSpec contains the email and username of the administrator
@@ -100,11 +182,57 @@ type EmbeddedObjectMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,12,rep,name=annotations"`
 }
 
+// RocketBackupSchedule configures scheduled backups of the database
+type RocketBackupSchedule struct {
+	// Schedule of the backups in cron format, e.g. "0 2 * * *" for nightly backups
+	Schedule string `json:"schedule"`
+	// Suspend pauses the scheduled backups
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Target is the storage the archives are written to
+	Target BackupTarget `json:"target"`
+	// Retention decides which archives are pruned after a backup, all archives are kept if it is empty
+	// +optional
+	Retention BackupRetention `json:"retention,omitempty"`
+}
+
+// BackupRetention limits the archives kept by scheduled backups
+type BackupRetention struct {
+	// Count is the number of archives kept, older archives are pruned
+	// +optional
+	Count int32 `json:"count,omitempty"`
+	// MaxAge of the archives, older archives are pruned. It is rounded to full minutes, e.g. 168h
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// RocketUpgradeStrategy configures the rollout of new Rocket.Chat versions.
+// Rocket.Chat migrates the database irreversibly on startup, so a backup is taken before a new version is rolled out.
+type RocketUpgradeStrategy struct {
+	// BackupTarget is the storage of the backup taken before a new version is rolled out.
+	// Defaults to the target of the scheduled backups, changing the version is refused without a target unless skipBackup is set.
+	// +optional
+	BackupTarget *BackupTarget `json:"backupTarget,omitempty"`
+	// SkipBackup rolls out new versions without taking a backup first
+	// +optional
+	SkipBackup bool `json:"skipBackup,omitempty"`
+	// ProgressDeadlineSeconds is the time a new version has to become ready before it is considered failed.
+	// Defaults to the progress deadline of deployments, 600 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// AutoRollback reverts a failed version to the last known good version
+	// and restores the database from the backup taken before the failed version was rolled out.
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
+}
+
 // RocketSpec defines the desired state of Rocket
 type RocketSpec struct {
-	// Replicas specifies how many Webserver Pods shall be created
+	// Replicas specifies how many Webserver Pods shall be created, defaults to 1.
+	// 0 scales the webserver down, the database keeps running.
 	// +optional
-	Replicas int32 `json:"replicas,omitempty"`
+	Replicas *int32 `json:"replicas,omitempty"`
 	// Version specifies the Rocket.Chat Container Image Version
 	// +optional
 	Version string `json:"version,omitempty"`
@@ -114,14 +242,341 @@ type RocketSpec struct {
 	Database RocketDatabase `json:"database,omitempty"`
 	// Hostname to use for the instance
 	IngressSpec RocketIngressSpec `json:"ingressSpec,omitempty"`
+	// Backup schedules backups of the database
+	// +optional
+	Backup *RocketBackupSchedule `json:"backup,omitempty"`
+	// UpgradeStrategy configures the rollout of new Rocket.Chat versions
+	// +optional
+	UpgradeStrategy RocketUpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// PodTemplate configures the resources and the scheduling of the Rocket.Chat pods
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+	// Overrides patch the resources generated for the Rocket before they are applied
+	// +optional
+	Overrides []ResourceOverride `json:"overrides,omitempty"`
+	// Uploads configures the storage of the files uploaded by the users,
+	// the storage configured in the administration of Rocket.Chat is kept if it is unset
+	// +optional
+	Uploads *RocketUploads `json:"uploads,omitempty"`
+	// Exposure decides how Rocket.Chat is reachable from outside the cluster, defaults to an ingress.
+	// The host and path of the ingressSpec are used by the HTTPRoute as well.
+	// +optional
+	Exposure *RocketExposure `json:"exposure,omitempty"`
+	// Settings overwrite administration settings of Rocket.Chat, the keys are setting IDs like Accounts_RegistrationForm.
+	// Overwritten settings can't be changed in the administration anymore.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+	// SettingsFrom overwrites settings with the keys of secrets and config maps, e.g. for OAuth client secrets.
+	// Later sources take precedence over earlier sources, the settings of the spec over all sources.
+	// The pods are restarted when a source changes, a missing source keeps the settings the pods were started with.
+	// +optional
+	SettingsFrom []SettingsSource `json:"settingsFrom,omitempty"`
+}
+
+// SettingsSource references a secret or a config map whose keys are setting IDs, exactly one of them has to be set
+type SettingsSource struct {
+	// SecretRef is the secret containing the settings
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// ConfigMapRef is the config map containing the settings
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+}
+
+// ExposureType is the kind of resource exposing Rocket.Chat
+// +kubebuilder:validation:Enum=Ingress;GatewayHTTPRoute;LoadBalancer;NodePort
+type ExposureType string
+
+const (
+	// ExposureIngress creates an ingress for the host of the ingressSpec
+	ExposureIngress ExposureType = "Ingress"
+	// ExposureGatewayHTTPRoute attaches a Gateway API HTTPRoute to an existing gateway
+	ExposureGatewayHTTPRoute ExposureType = "GatewayHTTPRoute"
+	// ExposureLoadBalancer exposes the service of the webserver with a load balancer
+	ExposureLoadBalancer ExposureType = "LoadBalancer"
+	// ExposureNodePort exposes the service of the webserver on a port of every node
+	ExposureNodePort ExposureType = "NodePort"
+)
+
+// RocketExposure configures how Rocket.Chat is exposed
+type RocketExposure struct {
+	// Type of the exposure
+	// +kubebuilder:default=Ingress
+	Type ExposureType `json:"type"`
+	// Gateway the HTTPRoute is attached to, required for the GatewayHTTPRoute exposure
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference references a Gateway of the Gateway API
+type GatewayReference struct {
+	// Name of the gateway
+	Name string `json:"name"`
+	// Namespace of the gateway, defaults to the namespace of the Rocket
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the listener of the gateway the route is attached to, all listeners if unset
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// UploadStorageType is the storage of the uploaded files
+// +kubebuilder:validation:Enum=GridFS;FileSystem;AmazonS3
+type UploadStorageType string
+
+const (
+	// UploadStorageGridFS stores the files in the database
+	UploadStorageGridFS UploadStorageType = "GridFS"
+	// UploadStorageFileSystem stores the files on a volume shared by all pods
+	UploadStorageFileSystem UploadStorageType = "FileSystem"
+	// UploadStorageAmazonS3 stores the files in a bucket of an S3 compatible object storage
+	UploadStorageAmazonS3 UploadStorageType = "AmazonS3"
+)
+
+// RocketUploads configures the storage of the uploaded files
+type RocketUploads struct {
+	// Type of the storage
+	Type UploadStorageType `json:"type"`
+	// ClaimName is the persistent volume claim mounted by every pod for the FileSystem storage.
+	// The claim has to support the ReadWriteMany access mode if there is more than one replica.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// S3 is the bucket for the AmazonS3 storage
+	// +optional
+	S3 *S3Uploads `json:"s3,omitempty"`
+}
+
+// S3Uploads is the bucket the uploaded files are stored in
+type S3Uploads struct {
+	// Bucket the files are stored in
+	Bucket string `json:"bucket"`
+	// Region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+	// Endpoint is the URL of an S3 compatible object storage, e.g. http://minio.minio:9000. Defaults to Amazon S3.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// ForcePathStyle addresses the bucket in the path instead of the hostname, which is required by most MinIO setups
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+	// CredentialsSecret contains the keys accessKey and secretKey to access the bucket
+	CredentialsSecret corev1.LocalObjectReference `json:"credentialsSecret"`
+}
+
+// OverridePatchType is the format of the patch of a ResourceOverride
+// +kubebuilder:validation:Enum=StrategicMerge;JSON
+type OverridePatchType string
+
+const (
+	// OverrideStrategicMergePatch merges the patch into the resource, lists are merged by the keys of their elements
+	OverrideStrategicMergePatch OverridePatchType = "StrategicMerge"
+	// OverrideJSONPatch applies a list of RFC 6902 operations to the resource
+	OverrideJSONPatch OverridePatchType = "JSON"
+)
+
+// ResourceOverride patches generated resources with fields the Rocket doesn't expose.
+// Patches can't change the names, the selectors or the owner references of the resources.
+type ResourceOverride struct {
+	// Kind of the patched resources, e.g. Deployment
+	Kind string `json:"kind"`
+	// NameSuffix selects the resource whose name is the name of the Rocket followed by the suffix, e.g. -rocketchat.
+	// Every resource of the kind is patched if it is empty.
+	// +optional
+	NameSuffix string `json:"nameSuffix,omitempty"`
+	// Type of the patch, defaults to StrategicMerge
+	// +optional
+	Type OverridePatchType `json:"type,omitempty"`
+	// Patch in YAML or JSON, an object for a strategic merge patch or a list of operations for a JSON patch
+	Patch string `json:"patch"`
+}
+
+// PodTemplateOverrides configures the resources and the scheduling of the pods of a component
+type PodTemplateOverrides struct {
+	// Resources replaces the default resource requests of the container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Affinity of the pods
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations of the pods
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// NodeSelector restricts the nodes the pods are scheduled on
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// TopologySpreadConstraints describe how the pods are spread across topology domains
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is the priority class of the pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 type RocketIngressSpec struct {
 	// Host is the hostname for ingress object
 	Host string `json:"host,omitempty"`
-	// Annotations to add to the ingress Object
+	// Path is the prefix Rocket.Chat is served under, e.g. /chat. Defaults to the root of the host.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Annotations to add to the ingress Object.
+	// They usually configure a specific ingress controller and aren't added to OpenShift routes.
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLS configures the certificate of the ingress, the secret <name>-tls is used if it is unset
+	// +optional
+	TLS *RocketIngressTLS `json:"tls,omitempty"`
+}
+
+// IngressTLSMode decides where the certificate of the ingress comes from
+// +kubebuilder:validation:Enum=None;Secret;Issuer
+type IngressTLSMode string
+
+const (
+	// IngressTLSNone serves Rocket.Chat without TLS
+	IngressTLSNone IngressTLSMode = "None"
+	// IngressTLSSecret uses the certificate of an existing secret
+	IngressTLSSecret IngressTLSMode = "Secret"
+	// IngressTLSIssuer requests the certificate from a cert-manager issuer
+	IngressTLSIssuer IngressTLSMode = "Issuer"
+)
+
+// RocketIngressTLS configures the certificate of the ingress
+type RocketIngressTLS struct {
+	// Mode decides where the certificate comes from
+	Mode IngressTLSMode `json:"mode"`
+	// SecretName is the secret containing the certificate for the Secret mode, defaults to <name>-tls.
+	// For the Issuer mode it is the secret cert-manager stores the certificate in.
+	// OpenShift routes terminate TLS at the router and reference the secret as external certificate,
+	// which requires OpenShift 4.16 or newer.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// IssuerRef references the cert-manager issuer for the Issuer mode
+	// +optional
+	IssuerRef *CertificateIssuerReference `json:"issuerRef,omitempty"`
+	// Termination of TLS by the OpenShift router. Defaults to Edge.
+	// Reencrypt adds a TLS proxy to the webserver pods serving a certificate of the OpenShift service CA,
+	// the router verifies it with the service CA. It requires OpenShift.
+	// +optional
+	Termination RouteTLSTermination `json:"termination,omitempty"`
+}
+
+// RouteTLSTermination is the TLS termination of an OpenShift route
+// +kubebuilder:validation:Enum=Edge;Reencrypt
+type RouteTLSTermination string
+
+const (
+	// RouteTerminationEdge terminates TLS at the router, the webserver is reached with plain http
+	RouteTerminationEdge RouteTLSTermination = "Edge"
+	// RouteTerminationReencrypt terminates TLS at the router and opens a new TLS connection to the webserver
+	RouteTerminationReencrypt RouteTLSTermination = "Reencrypt"
+)
+
+// CertificateIssuerReference references a cert-manager Issuer or ClusterIssuer
+type CertificateIssuerReference struct {
+	// Name of the issuer
+	Name string `json:"name"`
+	// Kind of the issuer, Issuer or ClusterIssuer. Defaults to Issuer.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer, defaults to cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// DatabaseUpgradeStatus records the steps of a major upgrade of the database, an interrupted upgrade is resumed from its phase
+type DatabaseUpgradeStatus struct {
+	// FromVersion is the version of the database before the upgrade
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version of the next major release the database is upgraded to
+	ToVersion string `json:"toVersion"`
+	// Phase is the current step of the upgrade
+	Phase DatabaseUpgradePhase `json:"phase"`
+	// Message describes the current step or the last error of the upgrade
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the upgrade started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the featureCompatibilityVersion was set
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// JobAttempts is the number of failed jobs setting the featureCompatibilityVersion
+	// +optional
+	JobAttempts int32 `json:"jobAttempts,omitempty"`
+	// RetryTime is the time the failed job setting the featureCompatibilityVersion is retried
+	// +optional
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+}
+
+// DatabaseCheckStatus describes the connectivity checks of an external database, they are repeated periodically
+type DatabaseCheckStatus struct {
+	// LastCheckTime is the time the last check finished
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Failures is the number of consecutive failed checks, failed checks are repeated with a growing backoff
+	// +optional
+	Failures int32 `json:"failures,omitempty"`
+}
+
+// UpgradeBackupStatus references the backup taken before a new version of Rocket.Chat was rolled out
+type UpgradeBackupStatus struct {
+	// FromVersion is the version of Rocket.Chat the backup was taken from
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version of Rocket.Chat rolled out after the backup
+	ToVersion string `json:"toVersion"`
+	// Backup is the name of the RocketBackup
+	Backup string `json:"backup"`
+	// Target is the storage the archive was written to
+	Target BackupTarget `json:"target"`
+	// Archive is the path of the archive on the claim or its key in the bucket
+	Archive string `json:"archive"`
+	// CompletionTime is the time the backup completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// PendingUpgradeStatus records a version change of Rocket.Chat waiting for the backup taken before its rollout
+type PendingUpgradeStatus struct {
+	// ToVersion is the version of Rocket.Chat rolled out after the backup
+	ToVersion string `json:"toVersion"`
+	// Generation is the generation of the Rocket the version change was detected in, it tells the backups of repeated upgrades apart
+	Generation int64 `json:"generation"`
+}
+
+// RollbackPhase is the step of the rollback of a failed Rocket.Chat version
+type RollbackPhase string
+
+const (
+	// RollbackPhaseRevertingVersion rolls the webserver back to the last known good version
+	RollbackPhaseRevertingVersion RollbackPhase = "RevertingVersion"
+	// RollbackPhaseRestoring restores the database from the backup taken before the failed version was rolled out
+	RollbackPhaseRestoring RollbackPhase = "Restoring"
+	// RollbackPhaseCompleted is set once the database was restored
+	RollbackPhaseCompleted RollbackPhase = "Completed"
+	// RollbackPhaseFailed is set if the database couldn't be restored
+	RollbackPhaseFailed RollbackPhase = "Failed"
+)
+
+// RollbackStatus records the rollback of a Rocket.Chat version which didn't become ready
+type RollbackStatus struct {
+	// FromVersion is the failed version of Rocket.Chat
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the last known good version of Rocket.Chat
+	ToVersion string `json:"toVersion"`
+	// Phase is the current step of the rollback
+	Phase RollbackPhase `json:"phase"`
+	// Message describes why the version was rolled back and the current step
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Restore is the name of the RocketRestore restoring the backup taken before the failed version was rolled out
+	// +optional
+	Restore string `json:"restore,omitempty"`
+	// StartTime is the time the failure was detected
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the rollback finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // RocketStatus defines the observed state of Rocket
@@ -139,8 +594,60 @@ type RocketStatus struct {
 	// True if all resources are in a ready state and all work is done.
 	Ready bool `json:"ready,omitempty"`
 	// External URL for accessing Rocket instance from outside the cluster.
+	// It is derived from the host, the TLS setting and the path of the ingress and set as ROOT_URL of Rocket.Chat.
 	// +optional
 	ExternalURL string `json:"externalURL,omitempty"`
+	// IngressAddress is the hostname or IP the ingress controller assigned to the ingress.
+	// +optional
+	IngressAddress string `json:"ingressAddress,omitempty"`
+	// ObservedGeneration is the generation of the Rocket spec the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the state of the Rocket resources.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// LastBackup is the completion time of the last successful scheduled backup.
+	// +optional
+	LastBackup *metav1.Time `json:"lastBackup,omitempty"`
+	// LastBackupError is the error of the last scheduled backup, it is empty if the last backup succeeded.
+	// +optional
+	LastBackupError string `json:"lastBackupError,omitempty"`
+	// WebserverVersion is the version of Rocket.Chat deployed by the operator.
+	// It only follows spec.version once the database runs a release supported by the new version.
+	// +optional
+	WebserverVersion string `json:"webserverVersion,omitempty"`
+	// LastKnownGoodVersion is the last version of Rocket.Chat which became ready.
+	// +optional
+	LastKnownGoodVersion string `json:"lastKnownGoodVersion,omitempty"`
+	// Rollback is the last rollback of a failed Rocket.Chat version.
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+	// PendingUpgrade is the version change of Rocket.Chat waiting for its backup.
+	// +optional
+	PendingUpgrade *PendingUpgradeStatus `json:"pendingUpgrade,omitempty"`
+	// UpgradeBackup references the backup taken before the last version change of Rocket.Chat,
+	// the database can be restored from it to roll the version back.
+	// +optional
+	UpgradeBackup *UpgradeBackupStatus `json:"upgradeBackup,omitempty"`
+	// DatabaseVersion is the version of mongodb running on the data directory, major upgrades only change it once they completed.
+	// +optional
+	DatabaseVersion string `json:"databaseVersion,omitempty"`
+	// FeatureCompatibilityVersion is the featureCompatibilityVersion of the database, e.g. 4.4
+	// +optional
+	FeatureCompatibilityVersion string `json:"featureCompatibilityVersion,omitempty"`
+	// DatabaseUpgrade is the last major upgrade of the database.
+	// +optional
+	DatabaseUpgrade *DatabaseUpgradeStatus `json:"databaseUpgrade,omitempty"`
+	// DatabaseCheck is the last connectivity check of an external database.
+	// +optional
+	DatabaseCheck *DatabaseCheckStatus `json:"databaseCheck,omitempty"`
+	// SettingsHash is the hash of the resource versions of spec.settingsFrom the pods were started with
+	// +optional
+	SettingsHash string `json:"settingsHash,omitempty"`
 }
 
 // EmbeddedPod contains metadata and status of a pod
@@ -177,6 +684,37 @@ type RocketList struct {
 	Items           []Rocket `json:"items,omitempty"`
 }
 
+// IsDatabaseUpgrading returns true while a major upgrade of the database is in progress
+func (r *Rocket) IsDatabaseUpgrading() bool {
+	upgrade := r.Status.DatabaseUpgrade
+	return upgrade != nil && upgrade.Phase != DatabaseUpgradeCompleted && upgrade.Phase != DatabaseUpgradeFailed
+}
+
+// IsDatabaseUpgradeFailed returns true if the major upgrade to the version of the spec failed
+func (r *Rocket) IsDatabaseUpgradeFailed() bool {
+	upgrade := r.Status.DatabaseUpgrade
+	return upgrade != nil && upgrade.Phase == DatabaseUpgradeFailed && upgrade.ToVersion == r.Spec.Database.Version
+}
+
+// HasExternalDatabase returns true if the database isn't deployed by the operator
+func (r *Rocket) HasExternalDatabase() bool {
+	return r.Spec.Database.External != nil
+}
+
+// ExposureType returns how Rocket.Chat is exposed, an ingress if no exposure is set
+func (r *Rocket) ExposureType() ExposureType {
+	if r.Spec.Exposure == nil || r.Spec.Exposure.Type == "" {
+		return ExposureIngress
+	}
+	return r.Spec.Exposure.Type
+}
+
+// IsRollingBack returns true while a failed version of Rocket.Chat is rolled back
+func (r *Rocket) IsRollingBack() bool {
+	rollback := r.Status.Rollback
+	return rollback != nil && rollback.Phase != RollbackPhaseCompleted && rollback.Phase != RollbackPhaseFailed
+}
+
 func init() {
 	SchemeBuilder.Register(&Rocket{}, &RocketList{})
 }
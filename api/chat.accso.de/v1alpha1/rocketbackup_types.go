@@ -0,0 +1,124 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupPhase is the phase of a RocketBackup
+type BackupPhase string
+
+var (
+	BackupPhasePending   BackupPhase = "Pending"
+	BackupPhaseRunning   BackupPhase = "Running"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+// BackupTarget is the storage the mongodump archives are written to, either a persistent volume claim or an S3 bucket
+type BackupTarget struct {
+	// ClaimName is the name of an existing persistent volume claim in the namespace of the backup
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// S3 is a bucket of an S3 compatible object storage, like AWS S3 or MinIO
+	// +optional
+	S3 *S3Target `json:"s3,omitempty"`
+}
+
+// S3Target is a bucket of an S3 compatible object storage
+type S3Target struct {
+	// Endpoint is the URL of the object storage, e.g. https://s3.amazonaws.com or http://minio.minio:9000
+	Endpoint string `json:"endpoint"`
+	// Bucket the archives are uploaded to
+	Bucket string `json:"bucket"`
+	// Prefix is the folder in the bucket the archives are uploaded to, e.g. backups/chat
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// CredentialsSecret contains the keys accessKey and secretKey to access the bucket
+	CredentialsSecret corev1.LocalObjectReference `json:"credentialsSecret"`
+}
+
+// RocketBackupSpec defines the desired state of RocketBackup
+type RocketBackupSpec struct {
+	// Rocket is the name of the Rocket to back up, it has to be in the namespace of the backup
+	Rocket string `json:"rocket"`
+	// Target is the storage the compressed archive is written to
+	Target BackupTarget `json:"target"`
+}
+
+// RocketBackupStatus defines the observed state of RocketBackup
+type RocketBackupStatus struct {
+	// Current phase of the backup.
+	Phase BackupPhase `json:"phase,omitempty"`
+	// Human-readable message indicating details about the current phase or error.
+	Message string `json:"message,omitempty"`
+	// Archive is the path of the compressed mongodump archive on the claim or its key in the bucket.
+	// +optional
+	Archive string `json:"archive,omitempty"`
+	// Size of the compressed archive.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+	// StartTime is the time the backup job was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the backup completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration of the backup job.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// RocketVersion is the Rocket.Chat version the backup was taken from.
+	// +optional
+	RocketVersion string `json:"rocketVersion,omitempty"`
+	// DatabaseVersion is the MongoDB version the backup was taken from.
+	// +optional
+	DatabaseVersion string `json:"databaseVersion,omitempty"`
+}
+
+// RocketBackup is the Schema for the rocketbackups API
+//+kubebuilder:printcolumn:name="Rocket",type=string,JSONPath=`.spec.rocket`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.status.size`
+//+kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type RocketBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RocketBackupSpec   `json:"spec,omitempty"`
+	Status RocketBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RocketBackupList contains a list of RocketBackup
+type RocketBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RocketBackup `json:"items,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RocketBackup{}, &RocketBackupList{})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestorePhase is the phase of a RocketRestore
+type RestorePhase string
+
+var (
+	RestorePhasePending     RestorePhase = "Pending"
+	RestorePhaseScalingDown RestorePhase = "ScalingDown"
+	RestorePhaseRestoring   RestorePhase = "Restoring"
+	RestorePhaseScalingUp   RestorePhase = "ScalingUp"
+	RestorePhaseCompleted   RestorePhase = "Completed"
+	RestorePhaseFailed      RestorePhase = "Failed"
+)
+
+// RestoreSource is the mongodump archive a Rocket is restored from, either on a persistent volume claim or in an S3 bucket
+type RestoreSource struct {
+	// ClaimName is the name of the persistent volume claim the archive is stored on
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// S3 is the object storage the archive is stored in
+	// +optional
+	S3 *S3Target `json:"s3,omitempty"`
+	// Archive is the path of the compressed mongodump archive on the claim or in the object storage including the bucket,
+	// e.g. the status.archive of a RocketBackup
+	Archive string `json:"archive"`
+}
+
+// RocketRestoreSpec defines the desired state of RocketRestore
+type RocketRestoreSpec struct {
+	// Rocket is the name of the Rocket to restore, it has to be in the namespace of the restore
+	Rocket string `json:"rocket"`
+	// Source is the archive the database of the Rocket is restored from
+	Source RestoreSource `json:"source"`
+}
+
+// RocketRestoreStatus defines the observed state of RocketRestore
+type RocketRestoreStatus struct {
+	// Current phase of the restore.
+	Phase RestorePhase `json:"phase,omitempty"`
+	// Human-readable message indicating details about the current phase or error.
+	Message string `json:"message,omitempty"`
+	// WebserverReplicas are the replicas of the webserver before it was scaled down.
+	// +optional
+	WebserverReplicas *int32 `json:"webserverReplicas,omitempty"`
+	// StartTime is the time the restore was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the restore completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// IsActive returns true while the restore is in progress
+func (r *RocketRestore) IsActive() bool {
+	return r.Status.Phase != RestorePhaseCompleted && r.Status.Phase != RestorePhaseFailed
+}
+
+// RocketRestore is the Schema for the rocketrestores API
+//+kubebuilder:printcolumn:name="Rocket",type=string,JSONPath=`.spec.rocket`
+//+kubebuilder:printcolumn:name="Archive",type=string,JSONPath=`.spec.source.archive`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type RocketRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RocketRestoreSpec   `json:"spec,omitempty"`
+	Status RocketRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RocketRestoreList contains a list of RocketRestore
+type RocketRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RocketRestore `json:"items,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RocketRestore{}, &RocketRestoreList{})
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// finishedJobCondition returns the Complete or Failed condition of the job, nil while the job is still running
+func finishedJobCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// jobTerminationMessage returns the termination message of the last terminated container of the pods of the job.
+// Containers of a succeeded job report their result in it, failed containers the tail of their logs.
+func jobTerminationMessage(ctx context.Context, client runtimeClient.Client, job *batchv1.Job) (string, error) {
+	podList := &corev1.PodList{}
+	listOpts := []runtimeClient.ListOption{
+		runtimeClient.InNamespace(job.Namespace),
+		runtimeClient.MatchingLabels{"job-name": job.Name},
+	}
+	if err := client.List(ctx, podList, listOpts...); err != nil {
+		return "", err
+	}
+
+	var message string
+	var finishedAt int64
+	for _, pod := range podList.Items {
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			terminated := status.State.Terminated
+			if terminated != nil && terminated.FinishedAt.Unix() >= finishedAt {
+				message = terminated.Message
+				finishedAt = terminated.FinishedAt.Unix()
+			}
+		}
+	}
+	return message, nil
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ownedResourceChanged filters update events of owned resources which don't change anything the controller cares about,
+// like resyncs or status updates which only bump the observedGeneration.
+// Create and delete events are always passed through, so deleted resources are recreated immediately.
+var ownedResourceChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(relevantState(e.ObjectOld), relevantState(e.ObjectNew))
+	},
+}
+
+// resourceState contains the parts of an owned resource which trigger a reconciliation when changed
+type resourceState struct {
+	Generation  int64
+	Deleting    bool
+	Labels      map[string]string
+	Annotations map[string]string
+	Content     interface{}
+}
+
+type conditionState struct {
+	Status string
+	Reason string
+}
+
+func relevantState(obj client.Object) resourceState {
+	state := resourceState{
+		Generation:  obj.GetGeneration(),
+		Deleting:    obj.GetDeletionTimestamp() != nil,
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+	}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		conditions := map[appsv1.DeploymentConditionType]conditionState{}
+		for _, condition := range o.Status.Conditions {
+			conditions[condition.Type] = conditionState{Status: string(condition.Status), Reason: condition.Reason}
+		}
+		state.Content = []interface{}{
+			o.Status.Replicas, o.Status.ReadyReplicas, o.Status.UpdatedReplicas, o.Status.AvailableReplicas, conditions,
+		}
+	case *appsv1.StatefulSet:
+		state.Content = []interface{}{
+			o.Status.Replicas, o.Status.ReadyReplicas, o.Status.CurrentRevision, o.Status.UpdateRevision,
+		}
+	case *corev1.Service:
+		state.Content = []interface{}{o.Spec, o.Status.LoadBalancer}
+	case *corev1.Secret:
+		state.Content = o.Data
+	case *corev1.ConfigMap:
+		state.Content = []interface{}{o.Data, o.BinaryData}
+	case *corev1.ServiceAccount:
+		state.Content = o.Secrets
+	case *rbacv1.Role:
+		state.Content = o.Rules
+	case *rbacv1.RoleBinding:
+		state.Content = []interface{}{o.RoleRef, o.Subjects}
+	case *networkingv1.Ingress:
+		state.Content = []interface{}{o.Spec, o.Status.LoadBalancer}
+	case *batchv1.CronJob:
+		// finished jobs are removed from the active jobs
+		state.Content = []interface{}{o.Spec, len(o.Status.Active), o.Status.LastSuccessfulTime}
+	case *chatv1alpha1.RocketBackup:
+		state.Content = o.Status.Phase
+	case *batchv1.Job:
+		state.Content = []interface{}{o.Status.Succeeded, o.Status.Failed, len(o.Status.Conditions)}
+	case *unstructured.Unstructured:
+		// the cert-manager certificate or the HTTPRoute, their status changes when they are issued or accepted
+		state.Content = []interface{}{o.Object["spec"], o.Object["status"]}
+	}
+	return state
+}
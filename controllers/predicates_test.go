@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+var _ = Describe("Owned resource predicate", func() {
+
+	objectMeta := metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 1, ResourceVersion: "1"}
+
+	table.DescribeTable("Should only pass updates the controller cares about",
+		func(old client.Object, mutate func(client.Object), want bool) {
+			updated := old.DeepCopyObject().(client.Object)
+			mutate(updated)
+			Expect(ownedResourceChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).Should(Equal(want))
+		},
+		table.Entry("resync without changes",
+			&appsv1.Deployment{ObjectMeta: objectMeta},
+			func(o client.Object) {},
+			false),
+		table.Entry("changed resource version",
+			&corev1.Secret{ObjectMeta: objectMeta},
+			func(o client.Object) { o.SetResourceVersion("2") },
+			false),
+		table.Entry("changed generation",
+			&appsv1.StatefulSet{ObjectMeta: objectMeta},
+			func(o client.Object) { o.SetGeneration(2) },
+			true),
+		table.Entry("changed labels",
+			&corev1.ConfigMap{ObjectMeta: objectMeta},
+			func(o client.Object) { o.SetLabels(map[string]string{"app": "test"}) },
+			true),
+		table.Entry("deletion started",
+			&corev1.Service{ObjectMeta: objectMeta},
+			func(o client.Object) { o.SetDeletionTimestamp(&metav1.Time{}) },
+			true),
+		table.Entry("deployment status only bumping the observed generation",
+			&appsv1.Deployment{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*appsv1.Deployment).Status.ObservedGeneration = 1 },
+			false),
+		table.Entry("deployment with more ready replicas",
+			&appsv1.Deployment{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*appsv1.Deployment).Status.ReadyReplicas = 1 },
+			true),
+		table.Entry("deployment condition changing its status",
+			&appsv1.Deployment{ObjectMeta: objectMeta, Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"},
+			}}},
+			func(o client.Object) {
+				condition := &o.(*appsv1.Deployment).Status.Conditions[0]
+				condition.Status, condition.Reason = corev1.ConditionFalse, "ProgressDeadlineExceeded"
+			},
+			true),
+		table.Entry("deployment condition only updating its timestamp",
+			&appsv1.Deployment{ObjectMeta: objectMeta, Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+			}}},
+			func(o client.Object) { o.(*appsv1.Deployment).Status.Conditions[0].LastUpdateTime = metav1.Now() },
+			false),
+		table.Entry("statefulset status only bumping the observed generation",
+			&appsv1.StatefulSet{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*appsv1.StatefulSet).Status.ObservedGeneration = 1 },
+			false),
+		table.Entry("statefulset rolled to a new revision",
+			&appsv1.StatefulSet{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*appsv1.StatefulSet).Status.CurrentRevision = "test-mongodb-2" },
+			true),
+		table.Entry("service changing its ports",
+			&corev1.Service{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*corev1.Service).Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80}} },
+			true),
+		table.Entry("load balancer getting an address",
+			&corev1.Service{ObjectMeta: objectMeta},
+			func(o client.Object) {
+				o.(*corev1.Service).Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			},
+			true),
+		table.Entry("secret changing its data",
+			&corev1.Secret{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*corev1.Secret).Data = map[string][]byte{"uri": []byte("mongodb://db")} },
+			true),
+		table.Entry("job becoming active",
+			&batchv1.Job{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*batchv1.Job).Status.Active = 1 },
+			false),
+		table.Entry("job succeeding",
+			&batchv1.Job{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*batchv1.Job).Status.Succeeded = 1 },
+			true),
+		table.Entry("cronjob only scheduling a job",
+			&batchv1.CronJob{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*batchv1.CronJob).Status.LastScheduleTime = &metav1.Time{} },
+			false),
+		table.Entry("cronjob finishing a job",
+			&batchv1.CronJob{ObjectMeta: objectMeta, Status: batchv1.CronJobStatus{Active: []corev1.ObjectReference{{Name: "test-1"}}}},
+			func(o client.Object) { o.(*batchv1.CronJob).Status.Active = nil },
+			true),
+		table.Entry("backup changing its phase",
+			&chatv1alpha1.RocketBackup{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*chatv1alpha1.RocketBackup).Status.Phase = chatv1alpha1.BackupPhaseCompleted },
+			true),
+		table.Entry("backup only changing its message",
+			&chatv1alpha1.RocketBackup{ObjectMeta: objectMeta},
+			func(o client.Object) { o.(*chatv1alpha1.RocketBackup).Status.Message = "waiting" },
+			false),
+		table.Entry("certificate being issued",
+			&unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"secretName": "test-tls"}}},
+			func(o client.Object) {
+				o.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{"notAfter": "2027-01-01T00:00:00Z"}
+			},
+			true),
+		table.Entry("certificate resync",
+			&unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"secretName": "test-tls"}}},
+			func(o client.Object) { o.SetResourceVersion("2") },
+			false),
+	)
+})
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// setBackupStatus sets lastBackup and lastBackupError from the last finished job of the backup cronJob
+func (r *RocketReconciler) setBackupStatus(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	if instance.Spec.Backup == nil {
+		return nil
+	}
+	cronJobName := new(model.MongodbBackupCronJobCreator).Selector(instance).Name
+	jobList := &batchv1.JobList{}
+	listOpts := []runtimeClient.ListOption{
+		runtimeClient.InNamespace(instance.Namespace),
+		runtimeClient.MatchingLabels{"app": instance.Name, "component": model.MongodbBackupComponentName},
+	}
+	if err := r.client.List(ctx, jobList, listOpts...); err != nil {
+		return err
+	}
+
+	var lastJob *batchv1.Job
+	var lastCondition *batchv1.JobCondition
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		owner := metav1.GetControllerOf(job)
+		if owner == nil || owner.Kind != "CronJob" || owner.Name != cronJobName {
+			continue
+		}
+		condition := finishedJobCondition(job)
+		if condition != nil && (lastCondition == nil || lastCondition.LastTransitionTime.Before(&condition.LastTransitionTime)) {
+			lastJob, lastCondition = job, condition
+		}
+	}
+	if lastCondition == nil {
+		return nil
+	}
+
+	if lastCondition.Type == batchv1.JobComplete {
+		instance.Status.LastBackup = &lastCondition.LastTransitionTime
+		instance.Status.LastBackupError = ""
+		return nil
+	}
+	message, err := jobTerminationMessage(ctx, r.client, lastJob)
+	if err != nil {
+		return err
+	}
+	instance.Status.LastBackupError = lastCondition.Message
+	if message != "" {
+		instance.Status.LastBackupError += ": " + message
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertificateExpiryWarning is the time before the expiry of the ingress certificate
+// from which on the certificate isn't considered ready anymore
+const CertificateExpiryWarning = 7 * 24 * time.Hour
+
+// certificateState is the state cert-manager reports for the certificate of the ingress
+type certificateState struct {
+	// Ready is true if cert-manager issued the certificate and stored it in its secret
+	Ready   bool
+	Message string
+	// NotAfter is the expiry of the issued certificate, nil if none was issued yet
+	NotAfter *time.Time
+}
+
+// certificateReadiness reads the state of the cert-manager certificate of the rocket, nil if the certificate doesn't exist yet
+func certificateReadiness(ctx context.Context, client runtimeClient.Client, instance *chatv1alpha1.Rocket) (*certificateState, error) {
+	cert := newUnstructured(model.CertificateGVK)
+	err := client.Get(ctx, new(model.RocketCertificateCreator).Selector(instance), cert)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state := &certificateState{Message: "Waiting for cert-manager to issue the certificate"}
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		state.Ready = condition["status"] == "True"
+		if message, ok := condition["message"].(string); ok && message != "" {
+			state.Message = message
+		}
+	}
+	if notAfter, found, _ := unstructured.NestedString(cert.Object, "status", "notAfter"); found {
+		expiry, err := time.Parse(time.RFC3339, notAfter)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing expiry %v of certificate %v: %w", notAfter, cert.GetName(), err)
+		}
+		state.NotAfter = &expiry
+	}
+	return state, nil
+}
+
+// manageCertificate sets the CertificateReady condition from the certificate issued by cert-manager.
+// It returns the duration after which the rocket has to be reconciled again to notice the certificate expiring,
+// zero if the certificate changing triggers the next reconciliation anyway.
+func (r *RocketReconciler) manageCertificate(ctx context.Context, instance *chatv1alpha1.Rocket) (time.Duration, error) {
+	if !new(model.RocketCertificateCreator).IsWanted(instance) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, chatv1alpha1.ConditionCertificateReady)
+		return 0, nil
+	}
+	if !r.platform.Certificates {
+		setCondition(instance, chatv1alpha1.ConditionCertificateReady, false, ReasonExposureAPIMissing,
+			"cert-manager isn't installed, install it and restart the operator to issue the certificate")
+		return 0, nil
+	}
+	state, err := certificateReadiness(ctx, r.client, instance)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading certificate of the ingress: %w", err)
+	}
+	switch {
+	case state == nil:
+		setCondition(instance, chatv1alpha1.ConditionCertificateReady, false, ReasonCertificateNotReady, "The certificate wasn't created yet")
+	case !state.Ready || state.NotAfter == nil:
+		setCondition(instance, chatv1alpha1.ConditionCertificateReady, false, ReasonCertificateNotReady, state.Message)
+	default:
+		remaining := time.Until(*state.NotAfter)
+		if remaining < CertificateExpiryWarning {
+			message := fmt.Sprintf("The certificate expires at %v and wasn't renewed yet", state.NotAfter.Format(time.RFC3339))
+			current := meta.FindStatusCondition(instance.Status.Conditions, chatv1alpha1.ConditionCertificateReady)
+			if current == nil || current.Reason != ReasonCertificateExpiring {
+				r.recorder.Event(instance, "Warning", ReasonCertificateExpiring, message)
+			}
+			setCondition(instance, chatv1alpha1.ConditionCertificateReady, false, ReasonCertificateExpiring, message)
+			return 0, nil
+		}
+		setCondition(instance, chatv1alpha1.ConditionCertificateReady, true, ReasonCertificateIssued,
+			fmt.Sprintf("The certificate is valid until %v", state.NotAfter.Format(time.RFC3339)))
+		return remaining - CertificateExpiryWarning, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("Rocket certificate", func() {
+
+	const (
+		RocketName      = "test-rocket-tls"
+		RocketNamespace = "default"
+	)
+
+	Context("When the ingress certificate is issued by cert-manager", func() {
+		It("Should create the Certificate and read its readiness", func() {
+			ctx := context.Background()
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      RocketName,
+					Namespace: RocketNamespace,
+				},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					IngressSpec: chatv1alpha1.RocketIngressSpec{
+						Host: "chat.example.com",
+						TLS: &chatv1alpha1.RocketIngressTLS{
+							Mode: chatv1alpha1.IngressTLSIssuer,
+							IssuerRef: &chatv1alpha1.CertificateIssuerReference{
+								Name: "letsencrypt",
+								Kind: "ClusterIssuer",
+							},
+						},
+					},
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, rocket)).Should(Succeed())
+
+			By("By applying the Certificate of the rocket")
+			runner := common.NewClusterActionRunner(ctx, k8sClient, scheme.Scheme, rocket)
+			Expect(runner.Create(new(model.RocketCertificateCreator).CreateResource(rocket))).Should(Succeed())
+
+			cert := &unstructured.Unstructured{}
+			cert.SetGroupVersionKind(model.CertificateGVK)
+			Expect(k8sClient.Get(ctx, new(model.RocketCertificateCreator).Selector(rocket), cert)).Should(Succeed())
+			Expect(cert.GetOwnerReferences()).Should(HaveLen(1))
+			Expect(cert.GetOwnerReferences()[0].Name).Should(Equal(RocketName))
+			secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+			Expect(secretName).Should(Equal(RocketName + "-tls"))
+			issuerKind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+			Expect(issuerKind).Should(Equal("ClusterIssuer"))
+
+			By("By checking the Certificate isn't ready before it's issued")
+			state, err := certificateReadiness(ctx, k8sClient, rocket)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(state).ShouldNot(BeNil())
+			Expect(state.Ready).Should(BeFalse())
+
+			By("By issuing the Certificate")
+			notAfter := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+			cert.Object["status"] = map[string]interface{}{
+				"notAfter": notAfter.Format(time.RFC3339),
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":    "Ready",
+						"status":  "True",
+						"message": "Certificate is up to date and has not expired",
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, cert)).Should(Succeed())
+
+			state, err = certificateReadiness(ctx, k8sClient, rocket)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(state.Ready).Should(BeTrue())
+			Expect(state.NotAfter).ShouldNot(BeNil())
+			Expect(state.NotAfter.Equal(notAfter)).Should(BeTrue())
+		})
+	})
+
+})
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// manageCompatibility checks spec.version against the database with the compatibility catalog.
+// It returns false if the release has to be held back, since the database runs a mongodb release it doesn't support.
+// The VersionsCompatible condition explains the database upgrade required for it.
+func (r *RocketReconciler) manageCompatibility(ctx context.Context, instance *chatv1alpha1.Rocket) (bool, error) {
+	status := &instance.Status
+	if status.WebserverVersion == "" {
+		// rockets deployed before the version was tracked keep the version of their deployment
+		deployment := &appsv1.Deployment{}
+		err := r.client.Get(ctx, new(model.RocketDeploymentCreator).Selector(instance), deployment)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("Error reading rocket.chat deployment: %w", err)
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if strings.HasPrefix(container.Image, model.RocketWebserverImage+":") {
+				status.WebserverVersion = strings.TrimPrefix(container.Image, model.RocketWebserverImage+":")
+			}
+		}
+	}
+
+	wanted, database := instance.Spec.Version, status.DatabaseVersion
+	if status.WebserverVersion == "" {
+		// new rockets have no release to hold back to
+		status.WebserverVersion = wanted
+	}
+	if err := r.catalog.CheckCompatibility(wanted, database); err != nil {
+		message := err.Error()
+		if status.WebserverVersion != wanted {
+			message = fmt.Sprintf("%v. Keeping Rocket.Chat %v", message, status.WebserverVersion)
+		}
+		setCondition(instance, chatv1alpha1.ConditionVersionsCompatible, false, ReasonDatabaseUpgradeRequired, message)
+		return false, nil
+	}
+	setCondition(instance, chatv1alpha1.ConditionVersionsCompatible, true, ReasonVersionsSupported,
+		fmt.Sprintf("Rocket.Chat %v supports mongodb %v", wanted, database))
+	return true, nil
+}
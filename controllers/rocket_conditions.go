@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the Rocket conditions
+const (
+	ReasonResourcesReady          = "ResourcesReady"
+	ReasonResourcesNotReady       = "ResourcesNotReady"
+	ReasonReconcileSucceeded      = "ReconcileSucceeded"
+	ReasonReconcileFailed         = "ReconcileFailed"
+	ReasonActionsApplied          = "ActionsApplied"
+	ReasonUpToDate                = "UpToDate"
+	ReasonFieldsTakenOver         = "FieldsTakenOver"
+	ReasonNoConflicts             = "NoConflicts"
+	ReasonRestoreInProgress       = "RestoreInProgress"
+	ReasonDatabaseUpToDate        = "DatabaseUpToDate"
+	ReasonUpgradeBlocked          = "UpgradeBlocked"
+	ReasonWaitingForDatabase      = "WaitingForDatabase"
+	ReasonUpgradeCompleted        = "UpgradeCompleted"
+	ReasonDatabaseUpgradeFailed   = "DatabaseUpgradeFailed"
+	ReasonVersionsSupported       = "VersionsSupported"
+	ReasonDatabaseUpgradeRequired = "DatabaseUpgradeRequired"
+	ReasonBackupTargetMissing     = "BackupTargetMissing"
+	ReasonUpgradeBackupFailed     = "UpgradeBackupFailed"
+	ReasonBackingUp               = "BackingUp"
+	ReasonWebserverFailed         = "WebserverFailed"
+	ReasonRollingBack             = "RollingBack"
+	ReasonRolledBack              = "RolledBack"
+	ReasonExternalDatabase        = "ExternalDatabase"
+	ReasonDatabaseReachable       = "DatabaseReachable"
+	ReasonDatabaseUnreachable     = "DatabaseUnreachable"
+	ReasonCertificateIssued       = "CertificateIssued"
+	ReasonCertificateNotReady     = "CertificateNotReady"
+	ReasonCertificateExpiring     = "CertificateExpiring"
+	ReasonExposureAPIMissing      = "ExposureAPIMissing"
+)
+
+// setCondition sets the condition of the given type on the rocket status
+func setCondition(instance *chatv1alpha1.Rocket, conditionType string, status bool, reason, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if status {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+}
+
+// setReadinessConditions sets the component conditions and the Ready condition from the readiness of the resources
+func setReadinessConditions(instance *chatv1alpha1.Rocket, readiness common.ResourcesReadiness) {
+	if instance.HasExternalDatabase() {
+		setExternalDatabaseCondition(instance, readiness.Database)
+	} else {
+		setReadyCondition(instance, chatv1alpha1.ConditionDatabaseReady, readiness.Database, "mongodb statefulSet")
+	}
+	setReadyCondition(instance, chatv1alpha1.ConditionWebserverReady, readiness.Webserver, "rocket.chat deployment")
+	if readiness.Exposed {
+		setReadyCondition(instance, chatv1alpha1.ConditionIngressReady, readiness.Ingress, "ingress")
+	} else {
+		// an ingress without host isn't created, Rocket.Chat is only reachable inside of the cluster
+		meta.RemoveStatusCondition(&instance.Status.Conditions, chatv1alpha1.ConditionIngressReady)
+	}
+	setReadyCondition(instance, chatv1alpha1.ConditionReady, readiness.Ready(), "database and webserver")
+}
+
+func setReadyCondition(instance *chatv1alpha1.Rocket, conditionType string, ready bool, component string) {
+	if ready {
+		setCondition(instance, conditionType, true, ReasonResourcesReady, fmt.Sprintf("The %v is ready", component))
+		return
+	}
+	setCondition(instance, conditionType, false, ReasonResourcesNotReady, fmt.Sprintf("The %v is not ready yet", component))
+}
+
+// setExternalDatabaseCondition sets the DatabaseReady condition from the connectivity check of the external database
+func setExternalDatabaseCondition(instance *chatv1alpha1.Rocket, reachable bool) {
+	check := instance.Status.DatabaseCheck
+	switch {
+	case reachable:
+		setCondition(instance, chatv1alpha1.ConditionDatabaseReady, true, ReasonDatabaseReachable, "The connectivity check reached the external database")
+	case check != nil && check.Failures > 0:
+		setCondition(instance, chatv1alpha1.ConditionDatabaseReady, false, ReasonDatabaseUnreachable,
+			fmt.Sprintf("The connectivity check can't reach the external database, it failed %d times", check.Failures))
+	default:
+		setCondition(instance, chatv1alpha1.ConditionDatabaseReady, false, ReasonResourcesNotReady, "Waiting for the connectivity check of the external database")
+	}
+}
+
+// setProgressingCondition sets the Progressing condition from the actions applied in this reconciliation
+func setProgressingCondition(instance *chatv1alpha1.Rocket, applied []string, ready bool) {
+	switch {
+	case len(applied) > 0:
+		setCondition(instance, chatv1alpha1.ConditionProgressing, true, ReasonActionsApplied, strings.Join(applied, ", "))
+	case !ready:
+		setCondition(instance, chatv1alpha1.ConditionProgressing, true, ReasonResourcesNotReady, "Waiting for resources to become ready")
+	default:
+		setCondition(instance, chatv1alpha1.ConditionProgressing, false, ReasonUpToDate, "All resources are up to date")
+	}
+}
+
+// setConflictCondition sets the FieldConflict condition from the conflicts of the last apply
+func setConflictCondition(instance *chatv1alpha1.Rocket, conflicts []string) {
+	if len(conflicts) > 0 {
+		setCondition(instance, chatv1alpha1.ConditionFieldConflict, true, ReasonFieldsTakenOver, strings.Join(conflicts, "; "))
+		return
+	}
+	setCondition(instance, chatv1alpha1.ConditionFieldConflict, false, ReasonNoConflicts, "No conflicts with other field managers")
+}
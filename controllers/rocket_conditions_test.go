@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+var _ = Describe("Rocket conditions", func() {
+
+	Context("When Rocket.Chat isn't exposed outside of the cluster", func() {
+		It("Should not report the ingress as not ready", func() {
+			rocket := &chatv1alpha1.Rocket{}
+			setReadinessConditions(rocket, common.ResourcesReadiness{Database: true, Webserver: true, Exposed: true})
+			Expect(meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionIngressReady)).ShouldNot(BeNil())
+
+			setReadinessConditions(rocket, common.ResourcesReadiness{Database: true, Webserver: true})
+			Expect(meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionIngressReady)).Should(BeNil())
+			Expect(meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionReady)).Should(BeTrue())
+		})
+	})
+
+	Context("When the conditions are set", func() {
+		It("Should record the generation they were observed at", func() {
+			rocket := &chatv1alpha1.Rocket{}
+			rocket.Generation = 3
+			setReadinessConditions(rocket, common.ResourcesReadiness{Database: true})
+			for _, condition := range rocket.Status.Conditions {
+				Expect(condition.ObservedGeneration).Should(Equal(int64(3)))
+			}
+			Expect(meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)).Should(BeTrue())
+			Expect(meta.IsStatusConditionFalse(rocket.Status.Conditions, chatv1alpha1.ConditionWebserverReady)).Should(BeTrue())
+			Expect(meta.IsStatusConditionFalse(rocket.Status.Conditions, chatv1alpha1.ConditionReady)).Should(BeTrue())
+		})
+
+		It("Should report the applied actions and the conflicts", func() {
+			rocket := &chatv1alpha1.Rocket{}
+			setProgressingCondition(rocket, []string{"Rocket Deployment"}, false)
+			progressing := meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionProgressing)
+			Expect(progressing.Reason).Should(Equal(ReasonActionsApplied))
+			Expect(progressing.Message).Should(Equal("Rocket Deployment"))
+
+			setProgressingCondition(rocket, nil, true)
+			Expect(meta.IsStatusConditionFalse(rocket.Status.Conditions, chatv1alpha1.ConditionProgressing)).Should(BeTrue())
+
+			setConflictCondition(rocket, []string{"spec.replicas of kubectl"})
+			Expect(meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionFieldConflict)).Should(BeTrue())
+			setConflictCondition(rocket, nil)
+			Expect(meta.IsStatusConditionFalse(rocket.Status.Conditions, chatv1alpha1.ConditionFieldConflict)).Should(BeTrue())
+		})
+	})
+
+	Context("When the database is external", func() {
+		It("Should report the result of the connectivity check", func() {
+			rocket := &chatv1alpha1.Rocket{}
+			rocket.Spec.Database.External = &chatv1alpha1.ExternalDatabase{}
+			setReadinessConditions(rocket, common.ResourcesReadiness{})
+			database := meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)
+			Expect(database.Reason).Should(Equal(ReasonResourcesNotReady))
+			Expect(database.Message).ShouldNot(ContainSubstring("statefulSet"))
+
+			rocket.Status.DatabaseCheck = &chatv1alpha1.DatabaseCheckStatus{Failures: 2}
+			setReadinessConditions(rocket, common.ResourcesReadiness{})
+			database = meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)
+			Expect(database.Reason).Should(Equal(ReasonDatabaseUnreachable))
+
+			rocket.Status.DatabaseCheck.Failures = 0
+			setReadinessConditions(rocket, common.ResourcesReadiness{Database: true})
+			database = meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)
+			Expect(database.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(database.Reason).Should(Equal(ReasonDatabaseReachable))
+		})
+	})
+
+})
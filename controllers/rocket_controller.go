@@ -20,26 +20,34 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
-	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
-	RequeueDelay                  = 30 * time.Second
-	RequeueDelayResourcesNotReady = 5 * time.Second
-	RequeueDelayError             = 5 * time.Second
+	RequeueDelay      = 30 * time.Second
+	RequeueDelayError = 5 * time.Second
 )
 
 var (
@@ -52,14 +60,18 @@ type RocketReconciler struct {
 	client   runtimeClient.Client
 	scheme   *runtime.Scheme
 	recorder record.EventRecorder
+	catalog  *model.CompatibilityCatalog
+	platform common.Platform
 	ctx      context.Context
 }
 
-func NewRocketReconciler(client runtimeClient.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *RocketReconciler {
+func NewRocketReconciler(client runtimeClient.Client, scheme *runtime.Scheme, recorder record.EventRecorder, catalog *model.CompatibilityCatalog, platform common.Platform) *RocketReconciler {
 	return &RocketReconciler{
 		client:   client,
 		scheme:   scheme,
 		recorder: recorder,
+		catalog:  catalog,
+		platform: platform,
 		ctx:      context.TODO(),
 	}
 }
@@ -70,7 +82,18 @@ func NewRocketReconciler(client runtimeClient.Client, scheme *runtime.Scheme, re
 //+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts;configmaps;secrets;services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create;update;patch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketrestores,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketbackups,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;patch;delete
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -92,28 +115,50 @@ func (r *RocketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, err
 	}
-	// set Default Versions if none specified
-	if r.setVersionsIfEmpty(instance) {
-		err := r.client.Update(ctx, instance)
-		if err != nil {
-			return r.manageError(ctx, instance, err)
+	if instance.DeletionTimestamp.IsZero() {
+		if err := r.ensureFinalizer(ctx, instance); err != nil {
+			return ctrl.Result{}, err
 		}
-		return ctrl.Result{Requeue: true}, nil
 	}
-
-	// append default labels to rocketchat object
-	if !util.HasDefaultLabels(instance) {
-		debugLog.Info("No labels found, applying default labels", "object", req.NamespacedName)
-		instance.Labels = util.MergeLabels(instance.Labels, util.DefaultLabels(instance.Name))
-		// update the rocket instance and requeue
-		err := r.client.Update(ctx, instance)
-		if err != nil {
+	// Defaults are set by the defaulting webhook on admission. Objects admitted without the webhook
+	// are defaulted in memory only, the controller never writes to the spec it reconciles.
+	model.SetRocketDefaults(instance)
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.manageDeletion(ctx, instance)
+	}
+	restore, err := r.activeRestore(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	if restore != nil {
+		return r.manageRestore(ctx, instance, restore)
+	}
+	started, err := r.manageDatabaseUpgrade(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	if started {
+		// the status update triggers the reconciliation rolling the statefulSet
+		return ctrl.Result{}, r.client.Status().Update(ctx, instance)
+	}
+	compatible, err := r.manageCompatibility(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	if compatible {
+		if err := r.manageWebserverUpgrade(ctx, instance); err != nil {
 			return r.manageError(ctx, instance, err)
 		}
-		return ctrl.Result{Requeue: true}, nil
 	}
+	if err := r.manageDatabaseConnectivity(ctx, instance); err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	if err := r.manageSettings(ctx, instance); err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+
 	// read current Cluster State
-	currentState, err := common.NewCurrentStateReader(ctx, r.client, instance)
+	currentState, err := common.NewCurrentStateReader(ctx, r.client, instance, r.platform)
 	if err != nil {
 		return r.manageError(ctx, instance, err)
 	}
@@ -122,13 +167,27 @@ func (r *RocketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return r.manageError(ctx, instance, err)
 	}
 
-	desiredState := common.NewDesiredState(currentState, instance)
+	desiredState, err := common.NewDesiredState(currentState, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	for _, drift := range desiredState.Drift() {
+		r.recorder.Eventf(instance, "Normal", "DriftDetected", "%v drifted from the desired state: %v", drift.Name, strings.Join(drift.Fields, ", "))
+	}
 	actionRunner := common.NewClusterActionRunner(ctx, r.client, r.scheme, instance)
 	err = actionRunner.RunAll(desiredState)
+	for _, conflict := range actionRunner.Conflicts() {
+		r.recorder.Eventf(instance, "Warning", "FieldConflict", "Took over fields from another field manager: %v", conflict)
+	}
+	setConflictCondition(instance, actionRunner.Conflicts())
 	if err != nil {
 		return r.manageError(ctx, instance, err)
 	}
-	return r.manageSuccess(ctx, instance, currentState)
+	// the last known good version is deployed again before the database is restored
+	if err := r.manageRollback(ctx, instance); err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	return r.manageSuccess(ctx, instance, currentState, actionRunner)
 
 }
 
@@ -160,20 +219,35 @@ func (r *RocketReconciler) setStatusPods(ctx context.Context, instance *chatv1al
 	return nil
 }
 
-// updates the versions of the rocket instance in the cluster to the default versions if none is specified
-// returns true if a versions had to be updated
-func (r *RocketReconciler) setVersionsIfEmpty(instance *chatv1alpha1.Rocket) bool {
-	var dbVersionEmpty, webserverVersionEmpty = false, false
-	if instance.Spec.Version == "" {
-		instance.Spec.Version = model.RocketWebserverDefaultVersion
-		webserverVersionEmpty = true
+// activeRestore returns the restore of the rocket that is in progress, nil if there is none
+func (r *RocketReconciler) activeRestore(ctx context.Context, instance *chatv1alpha1.Rocket) (*chatv1alpha1.RocketRestore, error) {
+	restores := &chatv1alpha1.RocketRestoreList{}
+	if err := r.client.List(ctx, restores, runtimeClient.InNamespace(instance.Namespace)); err != nil {
+		return nil, fmt.Errorf("Error listing restores: %w", err)
 	}
-	if instance.Spec.Database.Version == "" {
-		instance.Spec.Database.Version = model.MongodbDefaultVersion
-		dbVersionEmpty = true
+	for i, restore := range restores.Items {
+		if restore.Spec.Rocket == instance.Name && restore.IsActive() {
+			return &restores.Items[i], nil
+		}
 	}
-	// return empty result if no patch was needed
-	return webserverVersionEmpty || dbVersionEmpty
+	return nil, nil
+}
+
+// manageRestore only reports the restore in the status, the resources of the rocket belong to the restore while it runs
+func (r *RocketReconciler) manageRestore(ctx context.Context, instance *chatv1alpha1.Rocket, restore *chatv1alpha1.RocketRestore) (ctrl.Result, error) {
+	message := fmt.Sprintf("Restore %v is in progress", restore.Name)
+	debugLog.Info(message, "object", instance.Name)
+
+	instance.Status.Ready = false
+	instance.Status.Message = message
+	instance.Status.ObservedGeneration = instance.Generation
+	setCondition(instance, chatv1alpha1.ConditionProgressing, true, ReasonRestoreInProgress, message)
+	setCondition(instance, chatv1alpha1.ConditionReady, false, ReasonRestoreInProgress, message)
+	if err := r.client.Status().Update(ctx, instance); err != nil {
+		controllerLog.Error(err, "unable to update status", "object", instance.Name)
+	}
+	// the restore triggers a new reconciliation when it finishes
+	return ctrl.Result{}, nil
 }
 
 func (r *RocketReconciler) manageError(ctx context.Context, instance *chatv1alpha1.Rocket, issue error) (ctrl.Result, error) {
@@ -182,6 +256,9 @@ func (r *RocketReconciler) manageError(ctx context.Context, instance *chatv1alph
 
 	instance.Status.Message = issue.Error()
 	instance.Status.Ready = false
+	instance.Status.ObservedGeneration = instance.Generation
+	setCondition(instance, chatv1alpha1.ConditionDegraded, true, ReasonReconcileFailed, issue.Error())
+	setCondition(instance, chatv1alpha1.ConditionReady, false, ReasonReconcileFailed, issue.Error())
 
 	err := r.client.Status().Update(ctx, instance)
 	if err != nil {
@@ -194,19 +271,35 @@ func (r *RocketReconciler) manageError(ctx context.Context, instance *chatv1alph
 	}, nil
 }
 
-func (r *RocketReconciler) manageSuccess(ctx context.Context, instance *chatv1alpha1.Rocket, currentState *common.ClusterStateReader) (ctrl.Result, error) {
+func (r *RocketReconciler) manageSuccess(ctx context.Context, instance *chatv1alpha1.Rocket, currentState *common.ClusterStateReader, actionRunner *common.ClusterActionRunner) (ctrl.Result, error) {
 	// Check if the resources are ready
-	resourcesReady, err := currentState.IsResourcesReady(instance)
+	readiness, err := currentState.IsResourcesReady(instance)
 	if err != nil {
 		return r.manageError(ctx, instance, fmt.Errorf("Error determining wether resources are ready: %w", err))
 	}
+	resourcesReady := readiness.Ready()
 
 	instance.Status.Ready = resourcesReady
+	instance.Status.ObservedGeneration = instance.Generation
+	setReadinessConditions(instance, readiness)
+	setProgressingCondition(instance, actionRunner.Applied(), resourcesReady)
+	setCondition(instance, chatv1alpha1.ConditionDegraded, false, ReasonReconcileSucceeded, "")
+	// a deployment updated by this reconciliation may still be read with the readiness of its previous version
+	if readiness.Webserver && len(actionRunner.Applied()) == 0 {
+		instance.Status.LastKnownGoodVersion = instance.Status.WebserverVersion
+	}
+	if readiness.WebserverFailure != "" {
+		r.manageWebserverFailure(instance, readiness.WebserverFailure)
+	}
 	instance.Status.Message = "Successfull"
 	err = r.setStatusPods(ctx, instance)
 	if err != nil {
 		return r.manageError(ctx, instance, fmt.Errorf("Error setting pod Status: %w", err))
 	}
+	err = r.setBackupStatus(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, fmt.Errorf("Error setting backup Status: %w", err))
+	}
 
 	// If resources are ready and we have not errored before now, we are in a reconciling phase
 	if resourcesReady {
@@ -215,10 +308,24 @@ func (r *RocketReconciler) manageSuccess(ctx context.Context, instance *chatv1al
 		instance.Status.Phase = chatv1alpha1.PhaseInitialising
 	}
 
-	//ingress := currentState.RocketIngress
-	//if ingress != nil {
-	//	instance.Status.ExternalURL = ingress.Status.
-	//}
+	instance.Status.ExternalURL = model.RocketExternalURL(instance)
+	if instance.Status.ExternalURL == "" && instance.ExposureType() == chatv1alpha1.ExposureLoadBalancer && readiness.IngressAddress != "" {
+		instance.Status.ExternalURL = "http://" + readiness.IngressAddress
+	}
+	instance.Status.IngressAddress = readiness.IngressAddress
+	if instance.ExposureType() == chatv1alpha1.ExposureGatewayHTTPRoute && !r.platform.HTTPRoutes {
+		setCondition(instance, chatv1alpha1.ConditionIngressReady, false, ReasonExposureAPIMissing,
+			"The cluster doesn't serve the Gateway API, install it and restart the operator to expose Rocket.Chat with a HTTPRoute")
+	}
+	if model.RouteReencrypt(instance) && !r.platform.Routes {
+		setCondition(instance, chatv1alpha1.ConditionIngressReady, false, ReasonExposureAPIMissing,
+			"Reencrypting TLS requires an OpenShift route, the ingress terminates TLS at the ingress controller")
+	}
+	certificateRequeue, err := r.manageCertificate(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+	requeue := earliestRequeue(certificateRequeue, databaseUpgradeRequeue(instance), databaseCheckRequeue(instance))
 
 	// only update, if there are changes
 	err = r.client.Status().Update(ctx, instance)
@@ -230,17 +337,69 @@ func (r *RocketReconciler) manageSuccess(ctx context.Context, instance *chatv1al
 		}, nil
 	}
 
+	if outdated := actionRunner.Outdated(); len(outdated) > 0 {
+		// resources missed by the cache are compared with the desired state once the cache caught up
+		debugLog.Info(fmt.Sprintf("resources %v already existed, reading the cluster state again", outdated), "object", instance.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	}
 	if resourcesReady {
 		controllerLog.Info("desired cluster state met", "object", instance.Name)
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeue}, nil
 	}
+	// readiness changes of the owned resources trigger a new reconciliation
 	debugLog.Info("desired cluster state met, but not all resources ready yet", "object", instance.Name)
-	return ctrl.Result{RequeueAfter: RequeueDelayResourcesNotReady}, nil
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// earliestRequeue returns the shortest of the given delays, delays of 0 don't requeue
+func earliestRequeue(delays ...time.Duration) time.Duration {
+	var earliest time.Duration
+	for _, delay := range delays {
+		if delay > 0 && (earliest == 0 || delay < earliest) {
+			earliest = delay
+		}
+	}
+	return earliest
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RocketReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	ownedOpts := builder.WithPredicates(ownedResourceChanged)
+	controller := ctrl.NewControllerManagedBy(mgr).
 		For(&chatv1alpha1.Rocket{}).
-		Complete(r)
+		Owns(&appsv1.Deployment{}, ownedOpts).
+		Owns(&appsv1.StatefulSet{}, ownedOpts).
+		Owns(&corev1.Service{}, ownedOpts).
+		Owns(&corev1.Secret{}, ownedOpts).
+		Owns(&corev1.ConfigMap{}, ownedOpts).
+		Owns(&corev1.ServiceAccount{}, ownedOpts).
+		Owns(&networkingv1.Ingress{}, ownedOpts).
+		Owns(&rbacv1.Role{}, ownedOpts).
+		Owns(&rbacv1.RoleBinding{}, ownedOpts).
+		Owns(&batchv1.CronJob{}, ownedOpts).
+		Owns(&batchv1.Job{}, ownedOpts).
+		Owns(&chatv1alpha1.RocketBackup{}, ownedOpts).
+		Watches(&source.Kind{Type: &chatv1alpha1.RocketRestore{}}, handler.EnqueueRequestsFromMapFunc(restoredRocket)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.rocketsReferencingSettings)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.rocketsReferencingSettings))
+	// certificates and routes are only watched in clusters with cert-manager, the Gateway API or OpenShift,
+	// otherwise the controller wouldn't start
+	optional := map[schema.GroupVersionKind]bool{
+		model.CertificateGVK: r.platform.Certificates,
+		model.HTTPRouteGVK:   r.platform.HTTPRoutes,
+		model.RouteGVK:       r.platform.Routes,
+	}
+	for gvk, served := range optional {
+		if served {
+			controller = controller.Owns(newUnstructured(gvk), ownedOpts)
+		}
+	}
+	return controller.Complete(r)
+}
+
+// newUnstructured returns an empty resource of a kind the operator doesn't have go types for
+func newUnstructured(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
 }
@@ -53,7 +53,7 @@ var _ = Describe("Rocket controller", func() {
 					Namespace: RocketNamespace,
 				},
 				Spec: chatv1alpha1.RocketSpec{
-					Replicas: 1,
+					Replicas: util.CreatePointerInt32(1),
 					IngressSpec: chatv1alpha1.RocketIngressSpec{
 						Host: "test",
 					},
@@ -85,7 +85,7 @@ var _ = Describe("Rocket controller", func() {
 				return true
 			}, timeout, interval).Should(BeTrue())
 			// Let's make sure our Schedule string value was properly converted/handled.
-			Expect(createdRocket.Spec.Replicas).Should(Equal(int32(1)))
+			Expect(createdRocket.Spec.Replicas).Should(Equal(util.CreatePointerInt32(1)))
 			/*
 				Now that we've created a Rocket in our test cluster, the next step is to write a test that actually tests our Rocket controller’s behavior.
 				Let’s test the Rocket controller’s logic responsible for updating Rocket.Status.Pods with actively running Pods.
@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DatabaseUpgradeDeadline is the time the statefulSet has to roll out the new version and become healthy
+	DatabaseUpgradeDeadline = 30 * time.Minute
+	// FeatureCompatibilityJobAttempts is the number of failed jobs after which the upgrade fails
+	FeatureCompatibilityJobAttempts = 3
+	// FeatureCompatibilityJobBackoff is the delay before a failed job is retried, multiplied by the failed attempts
+	FeatureCompatibilityJobBackoff = time.Minute
+)
+
+// manageDatabaseUpgrade tracks the version of the database in the status and moves major upgrades through their phases.
+// The statefulSet is rolled to the next major version by the desired state, since its image is derived from the status.
+// It returns true if an upgrade was started, the status has to be persisted before the statefulSet rolls,
+// so an interrupted reconciliation resumes the upgrade instead of rolling the statefulSet back.
+func (r *RocketReconciler) manageDatabaseUpgrade(ctx context.Context, instance *chatv1alpha1.Rocket) (bool, error) {
+	if instance.HasExternalDatabase() {
+		// external databases are upgraded by their operators, the version in the spec is taken as it is
+		instance.Status.DatabaseVersion = instance.Spec.Database.Version
+		instance.Status.FeatureCompatibilityVersion = ""
+		instance.Status.DatabaseUpgrade = nil
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonExternalDatabase, "Mongodb is managed externally")
+		return false, nil
+	}
+	sts := &appsv1.StatefulSet{}
+	err := r.client.Get(ctx, new(model.MongodbStatefulSetCreator).Selector(instance), sts)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("Error reading mongodb statefulSet: %w", err)
+		}
+		sts = nil
+	}
+
+	status := &instance.Status
+	if status.DatabaseVersion == "" {
+		// rockets deployed before the version was tracked keep the version of their statefulSet
+		status.DatabaseVersion = instance.Spec.Database.Version
+		if version := mongodbImageVersion(sts); version != "" {
+			status.DatabaseVersion = version
+		}
+		status.FeatureCompatibilityVersion, _ = model.MongodbReleaseSeries(status.DatabaseVersion)
+	}
+	if instance.IsDatabaseUpgrading() {
+		return false, r.advanceDatabaseUpgrade(ctx, instance, sts)
+	}
+	if instance.IsDatabaseUpgradeFailed() {
+		// retrying the same version would fail the same way, the spec has to be changed first
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonDatabaseUpgradeFailed,
+			fmt.Sprintf("%v, change spec.database.version to roll back to %v or to retry", status.DatabaseUpgrade.Message, status.DatabaseVersion))
+		return false, nil
+	}
+
+	wanted := instance.Spec.Database.Version
+	major, err := model.IsMongodbMajorUpgrade(status.DatabaseVersion, wanted)
+	if err != nil {
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonUpgradeBlocked, err.Error())
+		return false, nil
+	}
+	if !major {
+		// versions of the same release series share the data directory format and are rolled out directly
+		status.DatabaseVersion = wanted
+		if status.DatabaseUpgrade != nil && status.DatabaseUpgrade.Phase == chatv1alpha1.DatabaseUpgradeFailed {
+			// the failed upgrade was rolled back, upgrading to its version again starts a new upgrade
+			status.DatabaseUpgrade = nil
+		}
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonDatabaseUpToDate,
+			fmt.Sprintf("Mongodb %v is up to date", wanted))
+		return false, nil
+	}
+	if webserver := model.RocketWebserverVersion(instance); r.catalog.CheckCompatibility(webserver, wanted) != nil {
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonUpgradeBlocked,
+			fmt.Sprintf("Rocket.Chat %v doesn't support mongodb %v, upgrade Rocket.Chat first", webserver, wanted))
+		return false, nil
+	}
+	if !isStatefulSetHealthy(sts) {
+		setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonWaitingForDatabase,
+			fmt.Sprintf("Waiting for a healthy replica set before upgrading mongodb from %v to %v", status.DatabaseVersion, wanted))
+		return false, nil
+	}
+
+	now := metav1.Now()
+	status.DatabaseUpgrade = &chatv1alpha1.DatabaseUpgradeStatus{
+		FromVersion: status.DatabaseVersion,
+		ToVersion:   wanted,
+		StartTime:   &now,
+	}
+	setDatabaseUpgradePhase(instance, chatv1alpha1.DatabaseUpgradeRollingOut, fmt.Sprintf("Rolling out mongodb %v", wanted))
+	r.recorder.Eventf(instance, "Normal", "DatabaseUpgradeStarted", "Upgrading mongodb from %v to %v", status.DatabaseVersion, wanted)
+	return true, nil
+}
+
+// advanceDatabaseUpgrade moves the upgrade to the next phase once the current one is done
+func (r *RocketReconciler) advanceDatabaseUpgrade(ctx context.Context, instance *chatv1alpha1.Rocket, sts *appsv1.StatefulSet) error {
+	upgrade := instance.Status.DatabaseUpgrade
+	switch upgrade.Phase {
+	case chatv1alpha1.DatabaseUpgradeRollingOut:
+		if !isStatefulSetRolledOut(sts, upgrade.ToVersion) {
+			r.checkDatabaseUpgradeDeadline(instance, fmt.Sprintf("Mongodb %v wasn't rolled out", upgrade.ToVersion))
+			return nil
+		}
+		setDatabaseUpgradePhase(instance, chatv1alpha1.DatabaseUpgradeWaitingForReplicaSet,
+			fmt.Sprintf("Rolled out mongodb %v, waiting for a healthy replica set", upgrade.ToVersion))
+		fallthrough
+	case chatv1alpha1.DatabaseUpgradeWaitingForReplicaSet:
+		if !isStatefulSetHealthy(sts) {
+			r.checkDatabaseUpgradeDeadline(instance, fmt.Sprintf("The replica set didn't become healthy with mongodb %v", upgrade.ToVersion))
+			return nil
+		}
+		job, err := r.createFeatureCompatibilityJob(ctx, instance)
+		if err != nil {
+			return err
+		}
+		setDatabaseUpgradePhase(instance, chatv1alpha1.DatabaseUpgradeSettingFeatureCompatibility, fmt.Sprintf("Running job %v", job.Name))
+		return nil
+	case chatv1alpha1.DatabaseUpgradeSettingFeatureCompatibility:
+		return r.checkFeatureCompatibilityJob(ctx, instance)
+	default:
+		return fmt.Errorf("Unknown phase %q of the database upgrade", upgrade.Phase)
+	}
+}
+
+// checkDatabaseUpgradeDeadline fails the upgrade if the statefulSet didn't become healthy in time
+func (r *RocketReconciler) checkDatabaseUpgradeDeadline(instance *chatv1alpha1.Rocket, reason string) {
+	upgrade := instance.Status.DatabaseUpgrade
+	if upgrade.StartTime == nil || time.Since(upgrade.StartTime.Time) < DatabaseUpgradeDeadline {
+		return
+	}
+	r.failDatabaseUpgrade(instance, fmt.Sprintf("%v within %v", reason, DatabaseUpgradeDeadline))
+}
+
+// failDatabaseUpgrade stops the upgrade, the new version stays deployed until the spec is changed
+func (r *RocketReconciler) failDatabaseUpgrade(instance *chatv1alpha1.Rocket, message string) {
+	upgrade := instance.Status.DatabaseUpgrade
+	controllerLog.Info(fmt.Sprintf("database upgrade failed: %v", message), "object", instance.Name)
+	upgrade.Phase = chatv1alpha1.DatabaseUpgradeFailed
+	upgrade.Message = fmt.Sprintf("Upgrading mongodb from %v to %v failed: %v", upgrade.FromVersion, upgrade.ToVersion, message)
+	upgrade.RetryTime = nil
+	setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonDatabaseUpgradeFailed, upgrade.Message)
+	r.recorder.Event(instance, "Warning", "DatabaseUpgradeFailed", upgrade.Message)
+}
+
+// databaseUpgradeRequeue returns the time until the deadline or the retry of the upgrade is due, 0 if there is none
+func databaseUpgradeRequeue(instance *chatv1alpha1.Rocket) time.Duration {
+	if !instance.IsDatabaseUpgrading() {
+		return 0
+	}
+	upgrade := instance.Status.DatabaseUpgrade
+	var due *metav1.Time
+	switch upgrade.Phase {
+	case chatv1alpha1.DatabaseUpgradeRollingOut, chatv1alpha1.DatabaseUpgradeWaitingForReplicaSet:
+		if upgrade.StartTime != nil {
+			deadline := metav1.NewTime(upgrade.StartTime.Add(DatabaseUpgradeDeadline))
+			due = &deadline
+		}
+	case chatv1alpha1.DatabaseUpgradeSettingFeatureCompatibility:
+		due = upgrade.RetryTime
+	}
+	if due == nil {
+		return 0
+	}
+	if remaining := time.Until(due.Time); remaining > RequeueDelayError {
+		return remaining
+	}
+	return RequeueDelayError
+}
+
+func (r *RocketReconciler) createFeatureCompatibilityJob(ctx context.Context, instance *chatv1alpha1.Rocket) (*batchv1.Job, error) {
+	job, err := model.MongodbFeatureCompatibilityJob(instance, instance.Status.DatabaseUpgrade.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+		return nil, err
+	}
+	if err := r.client.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("Error creating featureCompatibilityVersion job: %w", err)
+	}
+	controllerLog.Info("started featureCompatibilityVersion job", "object", instance.Name, "job", job.Name)
+	return job, nil
+}
+
+// checkFeatureCompatibilityJob completes the upgrade once the job succeeded.
+// Failed jobs are deleted and retried with a growing backoff, the upgrade fails after FeatureCompatibilityJobAttempts.
+func (r *RocketReconciler) checkFeatureCompatibilityJob(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	upgrade := instance.Status.DatabaseUpgrade
+	job := &batchv1.Job{}
+	err := r.client.Get(ctx, runtimeClient.ObjectKey{Namespace: instance.Namespace, Name: instance.Name + model.MongodbUpgradeJobSuffix}, job)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if upgrade.RetryTime != nil && time.Now().Before(upgrade.RetryTime.Time) {
+			// the reconciliation is requeued once the backoff elapsed
+			return nil
+		}
+		// the job failed or was removed before it finished
+		if _, err := r.createFeatureCompatibilityJob(ctx, instance); err != nil {
+			return err
+		}
+		upgrade.RetryTime = nil
+		return nil
+	}
+	condition := finishedJobCondition(job)
+	if condition == nil {
+		// the job triggers a new reconciliation when it finishes
+		return nil
+	}
+
+	propagation := runtimeClient.PropagationPolicy(metav1.DeletePropagationBackground)
+	if condition.Type == batchv1.JobFailed {
+		// a set retry time means the failure was counted already and the deletion isn't in the cache yet
+		if upgrade.RetryTime == nil {
+			message, err := jobTerminationMessage(ctx, r.client, job)
+			if err != nil {
+				return fmt.Errorf("Error reading result of job %v: %w", job.Name, err)
+			}
+			upgrade.JobAttempts++
+			failure := fmt.Sprintf("Setting the featureCompatibilityVersion failed %v times: %v: %v",
+				upgrade.JobAttempts, condition.Message, strings.TrimSpace(message))
+			if upgrade.JobAttempts >= FeatureCompatibilityJobAttempts {
+				r.failDatabaseUpgrade(instance, failure)
+			} else {
+				retry := metav1.NewTime(time.Now().Add(time.Duration(upgrade.JobAttempts) * FeatureCompatibilityJobBackoff))
+				upgrade.RetryTime = &retry
+				upgrade.Message = fmt.Sprintf("%v, retrying at %v", failure, retry.Format(time.RFC3339))
+				r.recorder.Event(instance, "Warning", "DatabaseUpgradeRetrying", upgrade.Message)
+				setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, true, string(upgrade.Phase), upgrade.Message)
+			}
+		}
+		if err := r.client.Delete(ctx, job, propagation); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("Error deleting failed job %v: %w", job.Name, err)
+		}
+		return nil
+	}
+
+	if err := r.client.Delete(ctx, job, propagation); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting job %v: %w", job.Name, err)
+	}
+	now := metav1.Now()
+	instance.Status.DatabaseVersion = upgrade.ToVersion
+	instance.Status.FeatureCompatibilityVersion, _ = model.MongodbReleaseSeries(upgrade.ToVersion)
+	upgrade.CompletionTime = &now
+	upgrade.Phase = chatv1alpha1.DatabaseUpgradeCompleted
+	upgrade.Message = fmt.Sprintf("Upgraded mongodb from %v to %v", upgrade.FromVersion, upgrade.ToVersion)
+	setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, false, ReasonUpgradeCompleted, upgrade.Message)
+	r.recorder.Event(instance, "Normal", "DatabaseUpgraded", upgrade.Message)
+	return nil
+}
+
+func setDatabaseUpgradePhase(instance *chatv1alpha1.Rocket, phase chatv1alpha1.DatabaseUpgradePhase, message string) {
+	controllerLog.Info(fmt.Sprintf("database upgrade phase %v: %v", phase, message), "object", instance.Name)
+	instance.Status.DatabaseUpgrade.Phase = phase
+	instance.Status.DatabaseUpgrade.Message = message
+	setCondition(instance, chatv1alpha1.ConditionDatabaseUpgrading, true, string(phase), message)
+}
+
+// mongodbImageVersion returns the tag of the mongodb image of the statefulSet, empty if there is none
+func mongodbImageVersion(sts *appsv1.StatefulSet) string {
+	if sts == nil {
+		return ""
+	}
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == model.MongodbComponentName {
+			return strings.TrimPrefix(container.Image, model.MongodbImage+":")
+		}
+	}
+	return ""
+}
+
+// isStatefulSetRolledOut returns true if every replica runs the given version
+func isStatefulSetRolledOut(sts *appsv1.StatefulSet, version string) bool {
+	if sts == nil || sts.Status.ObservedGeneration < sts.Generation || mongodbImageVersion(sts) != version {
+		return false
+	}
+	return sts.Spec.Replicas != nil && sts.Status.UpdatedReplicas == *sts.Spec.Replicas &&
+		sts.Status.CurrentRevision == sts.Status.UpdateRevision
+}
+
+// isStatefulSetHealthy returns true if all replicas of the statefulSet are ready
+func isStatefulSetHealthy(sts *appsv1.StatefulSet) bool {
+	if sts == nil || sts.Spec.Replicas == nil {
+		return false
+	}
+	return sts.Status.Replicas == *sts.Spec.Replicas && sts.Status.ReadyReplicas == *sts.Spec.Replicas
+}
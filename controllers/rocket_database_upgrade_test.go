@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+var _ = Describe("Rocket database upgrade", func() {
+
+	Context("When the replica set doesn't become healthy", func() {
+		It("Should fail the upgrade after the deadline", func() {
+			ctx := context.Background()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := NewRocketReconciler(k8sClient, scheme.Scheme, recorder, model.DefaultCompatibilityCatalog(), common.Platform{})
+			start := metav1.NewTime(time.Now().Add(-time.Minute))
+			rocket := &chatv1alpha1.Rocket{
+				Spec: chatv1alpha1.RocketSpec{Database: chatv1alpha1.RocketDatabase{Version: "5.0.3"}},
+				Status: chatv1alpha1.RocketStatus{
+					DatabaseVersion: "4.4.10",
+					DatabaseUpgrade: &chatv1alpha1.DatabaseUpgradeStatus{
+						FromVersion: "4.4.10",
+						ToVersion:   "5.0.3",
+						Phase:       chatv1alpha1.DatabaseUpgradeWaitingForReplicaSet,
+						StartTime:   &start,
+					},
+				},
+			}
+			Expect(reconciler.advanceDatabaseUpgrade(ctx, rocket, nil)).Should(Succeed())
+			Expect(rocket.IsDatabaseUpgrading()).Should(BeTrue())
+			Expect(databaseUpgradeRequeue(rocket)).Should(BeNumerically("~", DatabaseUpgradeDeadline-time.Minute, time.Minute))
+
+			start = metav1.NewTime(time.Now().Add(-DatabaseUpgradeDeadline))
+			Expect(reconciler.advanceDatabaseUpgrade(ctx, rocket, nil)).Should(Succeed())
+			Expect(rocket.Status.DatabaseUpgrade.Phase).Should(Equal(chatv1alpha1.DatabaseUpgradeFailed))
+			Expect(rocket.IsDatabaseUpgradeFailed()).Should(BeTrue())
+			Expect(databaseUpgradeRequeue(rocket)).Should(BeZero())
+			condition := meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseUpgrading)
+			Expect(condition.Reason).Should(Equal(ReasonDatabaseUpgradeFailed))
+			Expect(recorder.Events).Should(Receive(ContainSubstring("DatabaseUpgradeFailed")))
+			Expect(model.MongodbVersion(rocket)).Should(Equal("5.0.3"))
+		})
+	})
+
+	Context("When a failed upgrade is rolled back", func() {
+		It("Should deploy the previous version and allow a new upgrade", func() {
+			ctx := context.Background()
+			reconciler := NewRocketReconciler(k8sClient, scheme.Scheme, record.NewFakeRecorder(10), model.DefaultCompatibilityCatalog(), common.Platform{})
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-rocket-upgrade-rollback", Namespace: "default"},
+				Spec:       chatv1alpha1.RocketSpec{Database: chatv1alpha1.RocketDatabase{Version: "5.0.3"}},
+				Status: chatv1alpha1.RocketStatus{
+					DatabaseVersion: "4.4.10",
+					DatabaseUpgrade: &chatv1alpha1.DatabaseUpgradeStatus{
+						FromVersion: "4.4.10",
+						ToVersion:   "5.0.3",
+						Phase:       chatv1alpha1.DatabaseUpgradeFailed,
+					},
+				},
+			}
+			started, err := reconciler.manageDatabaseUpgrade(ctx, rocket)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(started).Should(BeFalse())
+			condition := meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseUpgrading)
+			Expect(condition.Reason).Should(Equal(ReasonDatabaseUpgradeFailed))
+			Expect(condition.Message).Should(ContainSubstring("roll back to 4.4.10"))
+			Expect(model.MongodbVersion(rocket)).Should(Equal("5.0.3"))
+
+			By("By changing the version back to the previous series")
+			rocket.Spec.Database.Version = "4.4.10"
+			started, err = reconciler.manageDatabaseUpgrade(ctx, rocket)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(started).Should(BeFalse())
+			Expect(rocket.Status.DatabaseUpgrade).Should(BeNil())
+			Expect(rocket.Status.DatabaseVersion).Should(Equal("4.4.10"))
+			Expect(model.MongodbVersion(rocket)).Should(Equal("4.4.10"))
+			condition = meta.FindStatusCondition(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseUpgrading)
+			Expect(condition.Reason).Should(Equal(ReasonDatabaseUpToDate))
+		})
+	})
+
+})
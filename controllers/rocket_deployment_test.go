@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("Rocket deployment", func() {
+
+	const (
+		RocketName      = "test-rocket-deployment"
+		RocketNamespace = "default"
+	)
+
+	Context("When the webserver deployment is applied", func() {
+		It("Should be accepted by server-side apply", func() {
+			ctx := context.Background()
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      RocketName,
+					Namespace: RocketNamespace,
+				},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					AdminSpec: &chatv1alpha1.RocketAdminSpec{
+						Username: "admin",
+						Email:    "admin@example.com",
+					},
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, rocket)).Should(Succeed())
+			model.SetRocketDefaults(rocket)
+
+			runner := common.NewClusterActionRunner(ctx, k8sClient, scheme.Scheme, rocket)
+			creator := new(model.RocketDeploymentCreator)
+			Expect(runner.Update(creator.CreateResource(rocket))).Should(Succeed())
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, creator.Selector(rocket), dep)).Should(Succeed())
+			var names []string
+			for _, env := range dep.Spec.Template.Spec.Containers[0].Env {
+				names = append(names, env.Name)
+			}
+			Expect(names).Should(ContainElements("ADMIN_USERNAME", "ADMIN_EMAIL", "ADMIN_PASS"))
+		})
+	})
+
+})
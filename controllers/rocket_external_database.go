@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DatabaseCheckInterval is the time between the connectivity checks of an external database
+	DatabaseCheckInterval = 5 * time.Minute
+	// DatabaseCheckBackoff is the delay before a failed connectivity check is repeated, it doubles with every failure
+	DatabaseCheckBackoff = 10 * time.Second
+)
+
+// manageDatabaseConnectivity records the result of the connectivity check of an external database
+// and deletes the finished check once the next one is due, so the check is created again by the desired state.
+// Successful checks are repeated every DatabaseCheckInterval, failed checks with a growing backoff.
+func (r *RocketReconciler) manageDatabaseConnectivity(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	if !instance.HasExternalDatabase() {
+		instance.Status.DatabaseCheck = nil
+		return nil
+	}
+	job := &batchv1.Job{}
+	err := r.client.Get(ctx, new(model.MongodbCheckJobCreator).Selector(instance), job)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("Error reading connectivity check job: %w", err)
+	}
+	condition := finishedJobCondition(job)
+	if condition == nil {
+		// the job triggers a new reconciliation when it finishes
+		return nil
+	}
+
+	if instance.Status.DatabaseCheck == nil {
+		instance.Status.DatabaseCheck = &chatv1alpha1.DatabaseCheckStatus{}
+	}
+	check := instance.Status.DatabaseCheck
+	finished := condition.LastTransitionTime
+	if check.LastCheckTime == nil || check.LastCheckTime.Before(&finished) {
+		// the result of the job isn't recorded yet
+		check.LastCheckTime = &finished
+		if condition.Type == batchv1.JobFailed {
+			message, err := jobTerminationMessage(ctx, r.client, job)
+			if err != nil {
+				return fmt.Errorf("Error reading result of job %v: %w", job.Name, err)
+			}
+			check.Failures++
+			r.recorder.Eventf(instance, "Warning", "DatabaseUnreachable", "Can't connect to the external database, retrying in %v: %v: %v",
+				databaseCheckDelay(check), condition.Message, strings.TrimSpace(message))
+		} else {
+			if check.Failures > 0 {
+				r.recorder.Event(instance, "Normal", "DatabaseReachable", "Connected to the external database again")
+			}
+			check.Failures = 0
+		}
+	}
+	if databaseCheckRequeue(instance) > 0 {
+		return nil
+	}
+
+	propagation := runtimeClient.PropagationPolicy(metav1.DeletePropagationBackground)
+	if err := r.client.Delete(ctx, job, propagation); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting finished job %v: %w", job.Name, err)
+	}
+	return nil
+}
+
+// databaseCheckDelay returns the time between the last and the next connectivity check
+func databaseCheckDelay(check *chatv1alpha1.DatabaseCheckStatus) time.Duration {
+	delay := DatabaseCheckBackoff
+	for i := int32(1); i < check.Failures && delay < DatabaseCheckInterval; i++ {
+		delay *= 2
+	}
+	if check.Failures == 0 || delay > DatabaseCheckInterval {
+		return DatabaseCheckInterval
+	}
+	return delay
+}
+
+// databaseCheckRequeue returns the time until the next connectivity check is due, 0 if it is due or running already
+func databaseCheckRequeue(instance *chatv1alpha1.Rocket) time.Duration {
+	check := instance.Status.DatabaseCheck
+	if !instance.HasExternalDatabase() || check == nil || check.LastCheckTime == nil {
+		return 0
+	}
+	if remaining := time.Until(check.LastCheckTime.Add(databaseCheckDelay(check))); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
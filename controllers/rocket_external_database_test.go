@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+var _ = Describe("Rocket external database", func() {
+
+	Context("When the connectivity check of an external database finished", func() {
+		It("Should repeat successful checks periodically and back off after failures", func() {
+			check := &chatv1alpha1.DatabaseCheckStatus{}
+			Expect(databaseCheckDelay(check)).Should(Equal(DatabaseCheckInterval))
+			check.Failures = 1
+			Expect(databaseCheckDelay(check)).Should(Equal(DatabaseCheckBackoff))
+			check.Failures = 3
+			Expect(databaseCheckDelay(check)).Should(Equal(4 * DatabaseCheckBackoff))
+			check.Failures = 20
+			Expect(databaseCheckDelay(check)).Should(Equal(DatabaseCheckInterval))
+
+			rocket := &chatv1alpha1.Rocket{}
+			rocket.Spec.Database.External = &chatv1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "mongodb"}}
+			finished := metav1.NewTime(time.Now().Add(-time.Minute))
+			rocket.Status.DatabaseCheck = &chatv1alpha1.DatabaseCheckStatus{LastCheckTime: &finished}
+			Expect(databaseCheckRequeue(rocket)).Should(BeNumerically("~", DatabaseCheckInterval-time.Minute, time.Second))
+			rocket.Status.DatabaseCheck.Failures = 1
+			Expect(databaseCheckRequeue(rocket)).Should(BeZero())
+		})
+	})
+
+})
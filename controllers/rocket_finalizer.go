@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensureFinalizer adds the finalizer to the rocket.
+// Only the finalizers are patched, the rocket may contain defaults set in memory.
+func (r *RocketReconciler) ensureFinalizer(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	if controllerutil.ContainsFinalizer(instance, model.RocketFinalizer) {
+		return nil
+	}
+	patch := runtimeClient.MergeFromWithOptions(instance.DeepCopy(), runtimeClient.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(instance, model.RocketFinalizer)
+	if err := r.client.Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("Error adding finalizer: %w", err)
+	}
+	return nil
+}
+
+// manageDeletion enforces the deletion policy of the database and removes the finalizer afterwards
+func (r *RocketReconciler) manageDeletion(ctx context.Context, instance *chatv1alpha1.Rocket) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, model.RocketFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	policy := instance.Spec.Database.DeletionPolicy
+	if policy == chatv1alpha1.DeletionPolicySnapshot && !r.platform.VolumeSnapshots {
+		// the rocket couldn't be deleted at all if the policy waited for an API the cluster doesn't serve
+		r.recorder.Event(instance, "Warning", "SnapshotAPIMissing",
+			"The cluster doesn't serve volume snapshots, retaining the persistent volume claims instead")
+		policy = chatv1alpha1.DeletionPolicyRetain
+	}
+	controllerLog.Info(fmt.Sprintf("enforcing deletion policy %v", policy), "object", instance.Name)
+
+	claims, err := r.databaseClaims(ctx, instance)
+	if err != nil {
+		return r.manageError(ctx, instance, err)
+	}
+
+	var retained []string
+	switch policy {
+	case chatv1alpha1.DeletionPolicyDelete:
+		err = r.deleteDatabaseData(ctx, instance, claims)
+	case chatv1alpha1.DeletionPolicySnapshot:
+		var ready bool
+		ready, retained, err = r.snapshotClaims(ctx, instance, claims)
+		if err == nil && !ready {
+			debugLog.Info("waiting for volume snapshots to become ready", "object", instance.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelay}, nil
+		}
+		if err == nil {
+			err = r.deleteObjects(ctx, instance, claimObjects(claims)...)
+		}
+		if err == nil {
+			var secrets []string
+			secrets, err = r.retainSecrets(ctx, instance)
+			retained = append(retained, secrets...)
+		}
+	default:
+		retained, err = r.retainObjects(ctx, instance, claimObjects(claims)...)
+		if err == nil {
+			var secrets []string
+			secrets, err = r.retainSecrets(ctx, instance)
+			retained = append(retained, secrets...)
+		}
+	}
+	if err != nil {
+		return r.manageError(ctx, instance, fmt.Errorf("Error enforcing deletion policy %v: %w", policy, err))
+	}
+
+	// the status is gone with the rocket, the retained resources are found by their RetainedFromAnnotation
+	if len(retained) > 0 {
+		r.recorder.Eventf(instance, "Normal", "Retained", "Deletion policy %v kept %v", policy, strings.Join(retained, ", "))
+	}
+
+	patch := runtimeClient.MergeFromWithOptions(instance.DeepCopy(), runtimeClient.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(instance, model.RocketFinalizer)
+	if err := r.client.Patch(ctx, instance, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("Error removing finalizer: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// databaseClaims lists the persistent volume claims created by the mongodb statefulSet
+func (r *RocketReconciler) databaseClaims(ctx context.Context, instance *chatv1alpha1.Rocket) ([]corev1.PersistentVolumeClaim, error) {
+	list := &corev1.PersistentVolumeClaimList{}
+	if err := r.client.List(ctx, list, runtimeClient.InNamespace(instance.Namespace)); err != nil {
+		return nil, fmt.Errorf("Error listing persistent volume claims: %w", err)
+	}
+	var claims []corev1.PersistentVolumeClaim
+	for _, claim := range list.Items {
+		if model.IsDatabaseClaim(instance, &claim) {
+			claims = append(claims, claim)
+		}
+	}
+	return claims, nil
+}
+
+func claimObjects(claims []corev1.PersistentVolumeClaim) []runtimeClient.Object {
+	objs := make([]runtimeClient.Object, 0, len(claims))
+	for i := range claims {
+		objs = append(objs, &claims[i])
+	}
+	return objs
+}
+
+// databaseSecrets returns the auth and the admin secret of the rocket, secrets that don't exist are skipped
+func (r *RocketReconciler) databaseSecrets(ctx context.Context, instance *chatv1alpha1.Rocket) ([]runtimeClient.Object, error) {
+	var secrets []runtimeClient.Object
+	for _, creator := range []model.ResourceCreator{new(model.MongodbAuthSecretCreator), new(model.RocketAdminSecretCreator)} {
+		secret := &corev1.Secret{}
+		err := r.client.Get(ctx, creator.Selector(instance), secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error getting %v: %w", creator.Name(), err)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func (r *RocketReconciler) deleteDatabaseData(ctx context.Context, instance *chatv1alpha1.Rocket, claims []corev1.PersistentVolumeClaim) error {
+	secrets, err := r.databaseSecrets(ctx, instance)
+	if err != nil {
+		return err
+	}
+	return r.deleteObjects(ctx, instance, append(claimObjects(claims), secrets...)...)
+}
+
+func (r *RocketReconciler) deleteObjects(ctx context.Context, instance *chatv1alpha1.Rocket, objs ...runtimeClient.Object) error {
+	for _, obj := range objs {
+		if err := r.client.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("Error deleting %v: %w", obj.GetName(), err)
+		}
+		r.recorder.Eventf(instance, "Normal", "Deleted", "Deleted %v by deletion policy", obj.GetName())
+	}
+	return nil
+}
+
+func (r *RocketReconciler) retainSecrets(ctx context.Context, instance *chatv1alpha1.Rocket) ([]string, error) {
+	secrets, err := r.databaseSecrets(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	return r.retainObjects(ctx, instance, secrets...)
+}
+
+// retainObjects removes the owner reference to the rocket, so the objects aren't garbage collected,
+// and marks them with the name of the rocket they were retained from
+func (r *RocketReconciler) retainObjects(ctx context.Context, instance *chatv1alpha1.Rocket, objs ...runtimeClient.Object) ([]string, error) {
+	var retained []string
+	for _, obj := range objs {
+		patch := runtimeClient.MergeFrom(obj.DeepCopyObject().(runtimeClient.Object))
+		var ownerRefs []metav1.OwnerReference
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.UID != instance.UID {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		obj.SetOwnerReferences(ownerRefs)
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[model.RetainedFromAnnotation] = instance.Name
+		obj.SetAnnotations(annotations)
+		if err := r.client.Patch(ctx, obj, patch); err != nil {
+			return nil, fmt.Errorf("Error retaining %v: %w", obj.GetName(), err)
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.scheme)
+		if err != nil {
+			return nil, err
+		}
+		retained = append(retained, fmt.Sprintf("%v/%v", gvk.Kind, obj.GetName()))
+	}
+	return retained, nil
+}
+
+// snapshotClaims takes a VolumeSnapshot of every claim, it returns true once all snapshots are ready to use
+func (r *RocketReconciler) snapshotClaims(ctx context.Context, instance *chatv1alpha1.Rocket, claims []corev1.PersistentVolumeClaim) (bool, []string, error) {
+	allReady := true
+	var snapshots []string
+	for i := range claims {
+		snapshot := model.VolumeSnapshot(instance, &claims[i])
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(model.VolumeSnapshotGVK)
+		err := r.client.Get(ctx, runtimeClient.ObjectKeyFromObject(snapshot), live)
+		if errors.IsNotFound(err) {
+			err = r.client.Create(ctx, snapshot)
+			if err == nil {
+				r.recorder.Eventf(instance, "Normal", "SnapshotCreated", "Taking volume snapshot %v of %v", snapshot.GetName(), claims[i].Name)
+			}
+			live = snapshot
+		}
+		if err != nil {
+			return false, nil, fmt.Errorf("Error taking volume snapshot of %v: %w", claims[i].Name, err)
+		}
+		ready, err := model.IsVolumeSnapshotReady(live)
+		if err != nil {
+			return false, nil, err
+		}
+		allReady = allReady && ready
+		snapshots = append(snapshots, fmt.Sprintf("%v/%v", model.VolumeSnapshotGVK.Kind, snapshot.GetName()))
+	}
+	return allReady, snapshots, nil
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("Rocket deletion policy", func() {
+
+	const (
+		RocketNamespace = "default"
+
+		timeout  = time.Second * 20
+		interval = time.Millisecond * 250
+	)
+
+	// deployRocket creates a rocket and a claim of its database,
+	// and waits until the controller added its finalizer and created the auth secret
+	deployRocket := func(ctx context.Context, name string, policy chatv1alpha1.DeletionPolicy) (*chatv1alpha1.Rocket, *corev1.PersistentVolumeClaim, *corev1.Secret) {
+		rocket := &chatv1alpha1.Rocket{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: RocketNamespace},
+			Spec: chatv1alpha1.RocketSpec{
+				Replicas: util.CreatePointerInt32(1),
+				Database: chatv1alpha1.RocketDatabase{
+					StorageSpec:    &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					DeletionPolicy: policy,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rocket)).Should(Succeed())
+
+		secret := &corev1.Secret{}
+		Eventually(func() bool {
+			if err := k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(rocket), rocket); err != nil {
+				return false
+			}
+			err := k8sClient.Get(ctx, new(model.MongodbAuthSecretCreator).Selector(rocket), secret)
+			return err == nil && controllerutil.ContainsFinalizer(rocket, model.RocketFinalizer)
+		}, timeout, interval).Should(BeTrue())
+
+		defaulted := rocket.DeepCopy()
+		model.SetRocketDefaults(defaulted)
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaulted.Spec.Database.StorageSpec.Name + "-" + name + model.MongodbStatefulSetSuffix + "-0",
+				Namespace: RocketNamespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: chatv1alpha1.SchemeGroupVersion.String(),
+					Kind:       "Rocket",
+					Name:       rocket.Name,
+					UID:        rocket.UID,
+				}},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}
+		Expect(model.IsDatabaseClaim(defaulted, claim)).Should(BeTrue())
+		Expect(k8sClient.Create(ctx, claim)).Should(Succeed())
+		return rocket, claim, secret
+	}
+
+	// waitForDeletion waits until the controller enforced the deletion policy and removed its finalizer
+	waitForDeletion := func(ctx context.Context, rocket *chatv1alpha1.Rocket) {
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(rocket), &chatv1alpha1.Rocket{})
+			return errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+	}
+
+	// isDeleted returns true if the object is gone or waits for the finalizers of other controllers
+	isDeleted := func(ctx context.Context, obj runtimeClient.Object) bool {
+		err := k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(obj), obj)
+		return errors.IsNotFound(err) || (err == nil && !obj.GetDeletionTimestamp().IsZero())
+	}
+
+	// expectRetained checks that the object isn't owned by the rocket anymore and names the rocket it was retained from
+	expectRetained := func(ctx context.Context, rocket *chatv1alpha1.Rocket, obj runtimeClient.Object) {
+		Expect(k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(obj), obj)).Should(Succeed())
+		Expect(obj.GetDeletionTimestamp().IsZero()).Should(BeTrue())
+		Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(model.RetainedFromAnnotation, rocket.Name))
+		for _, ref := range obj.GetOwnerReferences() {
+			Expect(ref.UID).ShouldNot(Equal(rocket.UID))
+		}
+	}
+
+	Context("When the deletion policy is Retain", func() {
+		It("Should keep the claims and the secrets without owner references", func() {
+			ctx := context.Background()
+			rocket, claim, secret := deployRocket(ctx, "test-rocket-retain", chatv1alpha1.DeletionPolicyRetain)
+
+			Expect(k8sClient.Delete(ctx, rocket)).Should(Succeed())
+			waitForDeletion(ctx, rocket)
+			expectRetained(ctx, rocket, claim)
+			expectRetained(ctx, rocket, secret)
+		})
+	})
+
+	Context("When the deletion policy is Delete", func() {
+		It("Should delete the claims and the secrets", func() {
+			ctx := context.Background()
+			rocket, claim, secret := deployRocket(ctx, "test-rocket-delete", chatv1alpha1.DeletionPolicyDelete)
+
+			Expect(k8sClient.Delete(ctx, rocket)).Should(Succeed())
+			waitForDeletion(ctx, rocket)
+			Expect(isDeleted(ctx, claim)).Should(BeTrue())
+			Expect(isDeleted(ctx, secret)).Should(BeTrue())
+		})
+	})
+
+	Context("When the deletion policy is Snapshot", func() {
+		It("Should wait for the snapshots before deleting the claims", func() {
+			ctx := context.Background()
+			rocket, claim, secret := deployRocket(ctx, "test-rocket-snapshot", chatv1alpha1.DeletionPolicySnapshot)
+
+			Expect(k8sClient.Delete(ctx, rocket)).Should(Succeed())
+			snapshot := model.VolumeSnapshot(rocket, claim)
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(model.VolumeSnapshotGVK)
+			Eventually(func() error {
+				return k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(snapshot), live)
+			}, timeout, interval).Should(Succeed())
+			Expect(live.GetOwnerReferences()).Should(BeEmpty())
+			name, _, _ := unstructured.NestedString(live.Object, "spec", "source", "persistentVolumeClaimName")
+			Expect(name).Should(Equal(claim.Name))
+
+			By("By keeping the claim until the snapshot is ready")
+			Expect(k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(claim), claim)).Should(Succeed())
+			Expect(claim.DeletionTimestamp.IsZero()).Should(BeTrue())
+
+			Expect(unstructured.SetNestedField(live.Object, true, "status", "readyToUse")).Should(Succeed())
+			Expect(k8sClient.Status().Update(ctx, live)).Should(Succeed())
+			// snapshots aren't watched, a change of the rocket triggers the reconciliation before the requeue
+			current := &chatv1alpha1.Rocket{}
+			Expect(k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(rocket), current)).Should(Succeed())
+			patch := runtimeClient.MergeFrom(current.DeepCopy())
+			current.Annotations = map[string]string{"test": "snapshot-ready"}
+			Expect(k8sClient.Patch(ctx, current, patch)).Should(Succeed())
+
+			waitForDeletion(ctx, rocket)
+			Expect(isDeleted(ctx, claim)).Should(BeTrue())
+			expectRetained(ctx, rocket, secret)
+		})
+	})
+
+})
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// manageWebserverFailure handles a version of Rocket.Chat which didn't become ready within its progress deadline.
+// With autoRollback the last known good version is deployed again, the database is restored by manageRollback afterwards.
+func (r *RocketReconciler) manageWebserverFailure(instance *chatv1alpha1.Rocket, failure string) {
+	status := &instance.Status
+	failed, good := status.WebserverVersion, status.LastKnownGoodVersion
+	message := fmt.Sprintf("Rocket.Chat %v didn't become ready: %v", failed, failure)
+	if !instance.Spec.UpgradeStrategy.AutoRollback || good == "" || good == failed || instance.IsRollingBack() {
+		r.recorder.Event(instance, "Warning", "WebserverFailed", message)
+		setCondition(instance, chatv1alpha1.ConditionDegraded, true, ReasonWebserverFailed, message)
+		return
+	}
+
+	now := metav1.Now()
+	status.Rollback = &chatv1alpha1.RollbackStatus{
+		FromVersion: failed,
+		ToVersion:   good,
+		Phase:       chatv1alpha1.RollbackPhaseRevertingVersion,
+		Message:     fmt.Sprintf("%v, rolling back to %v", message, good),
+		StartTime:   &now,
+	}
+	status.WebserverVersion = good
+	r.recorder.Eventf(instance, "Warning", "RollingBack", "%v, rolling back to the last known good version %v", message, good)
+	setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, true, ReasonRollingBack, status.Rollback.Message)
+}
+
+// manageRollback restores the database once the last known good version was deployed again.
+// Rocket.Chat migrated the database when the failed version started, so the backup taken before its rollout is restored.
+// The Rocket isn't reconciled while the restore runs, the rollback is completed with the next reconciliation.
+func (r *RocketReconciler) manageRollback(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	if !instance.IsRollingBack() {
+		return nil
+	}
+	rollback, backup := instance.Status.Rollback, instance.Status.UpgradeBackup
+	if backup == nil || backup.ToVersion != rollback.FromVersion {
+		message := fmt.Sprintf("Rolled back from %v to %v, the database wasn't restored since no backup was taken before %[1]v was rolled out",
+			rollback.FromVersion, rollback.ToVersion)
+		r.recorder.Event(instance, "Warning", "RolledBack", message)
+		r.finishRollback(instance, chatv1alpha1.RollbackPhaseCompleted, message)
+		return nil
+	}
+
+	restore := model.RollbackRestore(instance, rollback, backup)
+	switch rollback.Phase {
+	case chatv1alpha1.RollbackPhaseRevertingVersion:
+		if err := controllerutil.SetControllerReference(instance, restore, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(ctx, restore); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("Error creating restore %v: %w", restore.Name, err)
+		}
+		rollback.Phase = chatv1alpha1.RollbackPhaseRestoring
+		rollback.Restore = restore.Name
+		rollback.Message = fmt.Sprintf("Restoring the database from backup %v", backup.Backup)
+		r.recorder.Eventf(instance, "Normal", "RestoringDatabase", "Restoring the database from backup %v taken before %v was rolled out", backup.Backup, rollback.FromVersion)
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, true, ReasonRollingBack, rollback.Message)
+	case chatv1alpha1.RollbackPhaseRestoring:
+		err := r.client.Get(ctx, runtimeClient.ObjectKeyFromObject(restore), restore)
+		if err != nil {
+			// a restore created in the last reconciliation might not be visible yet
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("Error reading restore %v: %w", restore.Name, err)
+		}
+		switch restore.Status.Phase {
+		case chatv1alpha1.RestorePhaseCompleted:
+			message := fmt.Sprintf("Rolled back from %v to %v and restored the database from backup %v", rollback.FromVersion, rollback.ToVersion, backup.Backup)
+			r.recorder.Event(instance, "Normal", "RolledBack", message)
+			r.finishRollback(instance, chatv1alpha1.RollbackPhaseCompleted, message)
+		case chatv1alpha1.RestorePhaseFailed:
+			message := fmt.Sprintf("Rolled back from %v to %v, but restoring the database failed: %v", rollback.FromVersion, rollback.ToVersion, restore.Status.Message)
+			r.recorder.Event(instance, "Warning", "RollbackFailed", message)
+			r.finishRollback(instance, chatv1alpha1.RollbackPhaseFailed, message)
+		}
+	}
+	return nil
+}
+
+func (r *RocketReconciler) finishRollback(instance *chatv1alpha1.Rocket, phase chatv1alpha1.RollbackPhase, message string) {
+	now := metav1.Now()
+	rollback := instance.Status.Rollback
+	rollback.Phase = phase
+	rollback.Message = message
+	rollback.CompletionTime = &now
+	setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, false, ReasonRolledBack, message)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("Rocket route", func() {
+
+	const (
+		RocketName      = "test-rocket-route"
+		RocketNamespace = "default"
+	)
+
+	Context("When the cluster serves OpenShift routes", func() {
+		It("Should discover the route API", func() {
+			discoveryClient, err := discovery.NewDiscoveryClientForConfig(testEnv.Config)
+			Expect(err).ShouldNot(HaveOccurred())
+			platform, err := common.DiscoverPlatform(discoveryClient)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(platform.Routes).Should(BeTrue())
+			Expect(platform.Certificates).Should(BeTrue())
+			Expect(platform.HTTPRoutes).Should(BeFalse())
+			Expect(platform.VolumeSnapshots).Should(BeTrue())
+		})
+
+		It("Should create the Route of the rocket", func() {
+			ctx := context.Background()
+			// the rocket isn't created, the running controller would delete the route otherwise.
+			// envtest runs no garbage collector removing the route of the missing owner.
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      RocketName,
+					Namespace: RocketNamespace,
+					UID:       "6f4c2a8e-route-test",
+				},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					IngressSpec: chatv1alpha1.RocketIngressSpec{
+						Host: "chat.apps.example.com",
+						Path: "/chat",
+					},
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			runner := common.NewClusterActionRunner(ctx, k8sClient, scheme.Scheme, rocket)
+			Expect(runner.Create(new(model.RouteCreator).CreateResource(rocket))).Should(Succeed())
+
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(model.RouteGVK)
+			Expect(k8sClient.Get(ctx, new(model.RouteCreator).Selector(rocket), route)).Should(Succeed())
+			Expect(route.GetOwnerReferences()).Should(HaveLen(1))
+			Expect(route.GetOwnerReferences()[0].Name).Should(Equal(RocketName))
+			host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+			Expect(host).Should(Equal("chat.apps.example.com"))
+			path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+			Expect(path).Should(Equal("/chat"))
+			termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+			Expect(termination).Should(Equal("edge"))
+		})
+	})
+
+})
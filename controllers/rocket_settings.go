@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// manageSettings hashes the versions of the settings sources into the status.
+// The hash is an annotation of the pod template, so the pods are restarted and read the new settings when a source changes.
+// Only the uid and resourceVersion of the sources are hashed, the values of the settings can't be guessed from the hash.
+// A missing source keeps the previous hash, so the other resources of the rocket are still managed.
+func (r *RocketReconciler) manageSettings(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	if len(instance.Spec.SettingsFrom) == 0 {
+		instance.Status.SettingsHash = ""
+		return nil
+	}
+	hash := sha256.New()
+	var unknown []string
+	for _, source := range instance.Spec.SettingsFrom {
+		obj, ids, err := r.settingsSource(ctx, instance.Namespace, source)
+		if errors.IsNotFound(err) {
+			r.recorder.Eventf(instance, "Warning", "SettingsSourceMissing", "%v, keeping the previous settings", err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if obj == nil {
+			continue
+		}
+		fmt.Fprintf(hash, "%T\x00%v\x00%v\x00%v\x00", obj, obj.GetName(), obj.GetUID(), obj.GetResourceVersion())
+		for _, id := range ids {
+			if !model.IsKnownSetting(id) {
+				unknown = append(unknown, id)
+			}
+		}
+	}
+	sum := fmt.Sprintf("%x", hash.Sum(nil))
+	// unknown settings are only reported when the sources change, they might be settings of a newer Rocket.Chat release
+	if sum != instance.Status.SettingsHash && len(unknown) > 0 {
+		sort.Strings(unknown)
+		r.recorder.Eventf(instance, "Warning", "UnknownSettings", "The settings sources contain unknown settings: %v", strings.Join(unknown, ", "))
+	}
+	instance.Status.SettingsHash = sum
+	return nil
+}
+
+// settingsSource reads the secret or config map of the source and returns it with the ids of its settings
+func (r *RocketReconciler) settingsSource(ctx context.Context, namespace string, source chatv1alpha1.SettingsSource) (runtimeClient.Object, []string, error) {
+	var ids []string
+	switch {
+	case source.SecretRef != nil:
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.SecretRef.Name}, secret); err != nil {
+			return nil, nil, fmt.Errorf("Error reading settings from secret %v: %w", source.SecretRef.Name, err)
+		}
+		for id := range secret.Data {
+			ids = append(ids, id)
+		}
+		return secret, ids, nil
+	case source.ConfigMapRef != nil:
+		configMap := &corev1.ConfigMap{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.ConfigMapRef.Name}, configMap); err != nil {
+			return nil, nil, fmt.Errorf("Error reading settings from config map %v: %w", source.ConfigMapRef.Name, err)
+		}
+		for id := range configMap.Data {
+			ids = append(ids, id)
+		}
+		return configMap, ids, nil
+	}
+	return nil, nil, nil
+}
+
+// rocketsReferencingSettings returns the rockets reading their settings from the secret or config map
+func (r *RocketReconciler) rocketsReferencingSettings(obj runtimeClient.Object) []reconcile.Request {
+	rockets := &chatv1alpha1.RocketList{}
+	if err := r.client.List(context.Background(), rockets, runtimeClient.InNamespace(obj.GetNamespace())); err != nil {
+		controllerLog.Error(err, "unable to list rockets referencing settings", "object", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, rocket := range rockets.Items {
+		for _, source := range rocket.Spec.SettingsFrom {
+			_, isSecret := obj.(*corev1.Secret)
+			if (isSecret && source.SecretRef != nil && source.SecretRef.Name == obj.GetName()) ||
+				(!isSecret && source.ConfigMapRef != nil && source.ConfigMapRef.Name == obj.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: rocket.Namespace, Name: rocket.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
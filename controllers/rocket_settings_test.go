@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("Rocket settings", func() {
+
+	const (
+		RocketName      = "test-rocket-settings"
+		RocketNamespace = "default"
+		SecretName      = "test-rocket-settings-oauth"
+	)
+
+	Context("When settings are read from a secret", func() {
+		It("Should hash the secret and reconcile the rocket when it changes", func() {
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: RocketNamespace},
+				StringData: map[string]string{"Accounts_OAuth_Custom-Keycloak-secret": "s3cr3t"},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      RocketName,
+					Namespace: RocketNamespace,
+				},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					SettingsFrom: []chatv1alpha1.SettingsSource{
+						{SecretRef: &corev1.LocalObjectReference{Name: SecretName}},
+					},
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, rocket)).Should(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := NewRocketReconciler(k8sClient, scheme.Scheme, recorder, model.DefaultCompatibilityCatalog(), common.Platform{})
+			Expect(reconciler.manageSettings(ctx, rocket)).Should(Succeed())
+			hash := rocket.Status.SettingsHash
+			Expect(hash).ShouldNot(BeEmpty())
+
+			By("By mapping the secret to the rocket")
+			requests := reconciler.rocketsReferencingSettings(secret)
+			Expect(requests).Should(HaveLen(1))
+			Expect(requests[0].Name).Should(Equal(RocketName))
+
+			By("By changing the secret")
+			Expect(k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(secret), secret)).Should(Succeed())
+			secret.Data["Accounts_OAuth_Custom-Keycloak-secret"] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).Should(Succeed())
+			Expect(reconciler.manageSettings(ctx, rocket)).Should(Succeed())
+			Expect(rocket.Status.SettingsHash).ShouldNot(Equal(hash))
+
+			By("By keeping the hash while the secret is unchanged")
+			hash = rocket.Status.SettingsHash
+			Expect(reconciler.manageSettings(ctx, rocket)).Should(Succeed())
+			Expect(rocket.Status.SettingsHash).Should(Equal(hash))
+
+			By("By keeping the hash when the secret is missing")
+			Expect(k8sClient.Delete(ctx, secret)).Should(Succeed())
+			Expect(reconciler.manageSettings(ctx, rocket)).Should(Succeed())
+			Expect(rocket.Status.SettingsHash).Should(Equal(hash))
+			Expect(recorder.Events).Should(Receive(ContainSubstring("SettingsSourceMissing")))
+		})
+	})
+
+})
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// manageWebserverUpgrade rolls out a new version of Rocket.Chat once a backup of the database was taken.
+// Rocket.Chat migrates the database on startup, the backup referenced in the status allows to restore the previous schema.
+func (r *RocketReconciler) manageWebserverUpgrade(ctx context.Context, instance *chatv1alpha1.Rocket) error {
+	status := &instance.Status
+	deployed, wanted := status.WebserverVersion, instance.Spec.Version
+	if deployed == wanted {
+		status.PendingUpgrade = nil
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, false, ReasonUpToDate, fmt.Sprintf("Rocket.Chat %v is rolled out", wanted))
+		return nil
+	}
+	if rollback := status.Rollback; rollback != nil && rollback.FromVersion == wanted {
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, instance.IsRollingBack(), ReasonRolledBack,
+			fmt.Sprintf("%v. Change spec.version to roll out another version", rollback.Message))
+		return nil
+	}
+	if instance.Spec.UpgradeStrategy.SkipBackup {
+		r.recorder.Eventf(instance, "Normal", "WebserverUpgradeStarted", "Rolling out Rocket.Chat %v without a backup", wanted)
+		status.WebserverVersion = wanted
+		status.PendingUpgrade = nil
+		return nil
+	}
+	target := model.UpgradeBackupTarget(instance)
+	if target == nil {
+		message := fmt.Sprintf("Keeping Rocket.Chat %v, set spec.upgradeStrategy.backupTarget or spec.upgradeStrategy.skipBackup to roll out %v", deployed, wanted)
+		// rockets admitted without the webhook can't be upgraded, the event is only emitted once per version
+		current := meta.FindStatusCondition(instance.Status.Conditions, chatv1alpha1.ConditionWebserverUpgrading)
+		if current == nil || current.Reason != ReasonBackupTargetMissing || current.Message != message {
+			r.recorder.Event(instance, "Warning", ReasonBackupTargetMissing, message)
+		}
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, false, ReasonBackupTargetMissing, message)
+		return nil
+	}
+
+	if status.PendingUpgrade == nil || status.PendingUpgrade.ToVersion != wanted {
+		status.PendingUpgrade = &chatv1alpha1.PendingUpgradeStatus{ToVersion: wanted, Generation: instance.Generation}
+	}
+	backup := model.UpgradeBackup(instance, status.PendingUpgrade, *target)
+	err := r.client.Get(ctx, runtimeClient.ObjectKeyFromObject(backup), backup)
+	if errors.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(instance, backup, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(ctx, backup); err != nil {
+			return fmt.Errorf("Error creating backup %v: %w", backup.Name, err)
+		}
+		r.recorder.Eventf(instance, "Normal", "UpgradeBackupStarted", "Backing up the database of Rocket.Chat %v to %v before rolling out %v", deployed, backup.Name, wanted)
+	} else if err != nil {
+		return fmt.Errorf("Error reading backup %v: %w", backup.Name, err)
+	}
+
+	switch backup.Status.Phase {
+	case chatv1alpha1.BackupPhaseCompleted:
+		status.UpgradeBackup = &chatv1alpha1.UpgradeBackupStatus{
+			FromVersion:    deployed,
+			ToVersion:      wanted,
+			Backup:         backup.Name,
+			Target:         backup.Spec.Target,
+			Archive:        backup.Status.Archive,
+			CompletionTime: backup.Status.CompletionTime,
+		}
+		status.WebserverVersion = wanted
+		status.PendingUpgrade = nil
+		r.recorder.Eventf(instance, "Normal", "WebserverUpgradeStarted", "Rolling out Rocket.Chat %v after backup %v", wanted, backup.Name)
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, false, ReasonUpToDate, fmt.Sprintf("Rocket.Chat %v is rolled out", wanted))
+	case chatv1alpha1.BackupPhaseFailed:
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, false, ReasonUpgradeBackupFailed,
+			fmt.Sprintf("Keeping Rocket.Chat %v, backup %v failed: %v. Delete the backup to retry", deployed, backup.Name, backup.Status.Message))
+	default:
+		// the backup triggers a new reconciliation when its phase changes
+		setCondition(instance, chatv1alpha1.ConditionWebserverUpgrading, true, ReasonBackingUp,
+			fmt.Sprintf("Waiting for backup %v before rolling out Rocket.Chat %v", backup.Name, wanted))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var backupLog = ctrl.Log.WithName("controllers").WithName("RocketBackup")
+
+// RocketBackupReconciler reconciles a RocketBackup object
+type RocketBackupReconciler struct {
+	client   runtimeClient.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+func NewRocketBackupReconciler(client runtimeClient.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *RocketBackupReconciler {
+	return &RocketBackupReconciler{
+		client:   client,
+		scheme:   scheme,
+		recorder: recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketbackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketbackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketbackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs a mongodump job for the backup and reports its result in the status of the backup.
+// A backup runs only once, completed and failed backups are never touched again.
+func (r *RocketBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	backup := &chatv1alpha1.RocketBackup{}
+	err := r.client.Get(ctx, req.NamespacedName, backup)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if backup.Status.Phase == chatv1alpha1.BackupPhaseCompleted || backup.Status.Phase == chatv1alpha1.BackupPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	job := &batchv1.Job{}
+	err = r.client.Get(ctx, runtimeClient.ObjectKey{Namespace: backup.Namespace, Name: backup.Name + model.MongodbBackupJobSuffix}, job)
+	if errors.IsNotFound(err) {
+		return r.startBackup(ctx, backup)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	condition := finishedJobCondition(job)
+	if condition == nil {
+		// the job triggers a new reconciliation when it finishes
+		return ctrl.Result{}, nil
+	}
+	message, err := jobTerminationMessage(ctx, r.client, job)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("Error reading result of job %v: %w", job.Name, err)
+	}
+
+	backup.Status.CompletionTime = &condition.LastTransitionTime
+	if job.Status.StartTime != nil {
+		backup.Status.Duration = &metav1.Duration{Duration: condition.LastTransitionTime.Sub(job.Status.StartTime.Time)}
+	}
+	if condition.Type == batchv1.JobFailed {
+		return r.manageBackupFailure(ctx, backup, fmt.Errorf("%v: %v", condition.Message, strings.TrimSpace(message)))
+	}
+	result, err := model.ParseBackupResult(message)
+	if err != nil {
+		return r.manageBackupFailure(ctx, backup, err)
+	}
+
+	backup.Status.Phase = chatv1alpha1.BackupPhaseCompleted
+	backup.Status.Archive = result.Archive
+	backup.Status.Size = resource.NewQuantity(result.Size, resource.BinarySI)
+	backup.Status.Message = fmt.Sprintf("Backup of %v completed", backup.Spec.Rocket)
+	r.recorder.Eventf(backup, "Normal", "Completed", "Wrote archive %v", backup.Status.Archive)
+	return ctrl.Result{}, r.client.Status().Update(ctx, backup)
+}
+
+// startBackup starts the mongodump job once the database of the rocket is ready
+func (r *RocketBackupReconciler) startBackup(ctx context.Context, backup *chatv1alpha1.RocketBackup) (ctrl.Result, error) {
+	rocket := &chatv1alpha1.Rocket{}
+	err := r.client.Get(ctx, runtimeClient.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.Rocket}, rocket)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if errors.IsNotFound(err) || !meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady) {
+		backup.Status.Phase = chatv1alpha1.BackupPhasePending
+		backup.Status.Message = fmt.Sprintf("Waiting for the database of %v to become ready", backup.Spec.Rocket)
+		if err := r.client.Status().Update(ctx, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: RequeueDelay}, nil
+	}
+	model.SetRocketDefaults(rocket)
+
+	job := model.MongodumpJob(backup, rocket)
+	if err := controllerutil.SetControllerReference(backup, job, r.scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.client.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return r.manageBackupFailure(ctx, backup, fmt.Errorf("Error creating backup job: %w", err))
+	}
+	backupLog.Info("started backup job", "object", backup.Name, "job", job.Name)
+	r.recorder.Eventf(backup, "Normal", "Started", "Started backup job %v", job.Name)
+
+	now := metav1.Now()
+	backup.Status.Phase = chatv1alpha1.BackupPhaseRunning
+	backup.Status.Message = fmt.Sprintf("Running backup job %v", job.Name)
+	backup.Status.StartTime = &now
+	backup.Status.Archive = model.BackupArchiveName(backup)
+	backup.Status.RocketVersion = model.RocketWebserverVersion(rocket)
+	backup.Status.DatabaseVersion = model.MongodbVersion(rocket)
+	return ctrl.Result{}, r.client.Status().Update(ctx, backup)
+}
+
+func (r *RocketBackupReconciler) manageBackupFailure(ctx context.Context, backup *chatv1alpha1.RocketBackup, issue error) (ctrl.Result, error) {
+	backupLog.Error(issue, "backup failed", "object", backup.Name)
+	r.recorder.Event(backup, "Warning", "Failed", issue.Error())
+
+	backup.Status.Phase = chatv1alpha1.BackupPhaseFailed
+	backup.Status.Message = issue.Error()
+	return ctrl.Result{}, r.client.Status().Update(ctx, backup)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RocketBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&chatv1alpha1.RocketBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
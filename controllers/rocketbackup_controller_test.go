@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("RocketBackup", func() {
+
+	const (
+		RocketName      = "test-rocket-backup"
+		RocketNamespace = "default"
+
+		timeout  = time.Second * 20
+		interval = time.Millisecond * 250
+	)
+
+	// backupPhase reads the backup and returns its phase
+	backupPhase := func(ctx context.Context, backup *chatv1alpha1.RocketBackup) func() chatv1alpha1.BackupPhase {
+		return func() chatv1alpha1.BackupPhase {
+			if err := k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(backup), backup); err != nil {
+				return ""
+			}
+			return backup.Status.Phase
+		}
+	}
+
+	// createBackup creates a backup of the rocket
+	createBackup := func(ctx context.Context, name string) *chatv1alpha1.RocketBackup {
+		backup := &chatv1alpha1.RocketBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: RocketNamespace},
+			Spec: chatv1alpha1.RocketBackupSpec{
+				Rocket: RocketName,
+				Target: chatv1alpha1.BackupTarget{ClaimName: "backups"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+		return backup
+	}
+
+	// finishJob sets the condition of the finished backup job like the job controller, which doesn't run in the test environment
+	finishJob := func(ctx context.Context, backup *chatv1alpha1.RocketBackup, conditionType batchv1.JobConditionType, message string) {
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, runtimeClient.ObjectKey{Namespace: RocketNamespace, Name: backup.Name + model.MongodbBackupJobSuffix}, job)).Should(Succeed())
+		started := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+		job.Status.StartTime = &started
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:               conditionType,
+			Status:             corev1.ConditionTrue,
+			Message:            message,
+			LastProbeTime:      metav1.Now(),
+			LastTransitionTime: metav1.Now(),
+		}}
+		if conditionType == batchv1.JobComplete {
+			job.Status.Succeeded = 1
+			job.Status.CompletionTime = &job.Status.Conditions[0].LastTransitionTime
+		} else {
+			job.Status.Failed = 1
+		}
+		Expect(k8sClient.Status().Update(ctx, job)).Should(Succeed())
+	}
+
+	Context("When a backup of a rocket is created", func() {
+		It("Should wait for the database, run the backup job and report its result", func() {
+			ctx := context.Background()
+
+			By("Waiting for the database of a missing rocket")
+			backup := createBackup(ctx, "test-backup-completed")
+			Eventually(backupPhase(ctx, backup), timeout, interval).Should(Equal(chatv1alpha1.BackupPhasePending))
+			Expect(backup.Status.Message).Should(ContainSubstring("Waiting for the database"))
+			job := &batchv1.Job{}
+			jobKey := runtimeClient.ObjectKey{Namespace: RocketNamespace, Name: backup.Name + model.MongodbBackupJobSuffix}
+			Expect(k8sClient.Get(ctx, jobKey, job)).ShouldNot(Succeed())
+
+			By("Reporting the statefulset of the rocket as ready")
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{Name: RocketName, Namespace: RocketNamespace},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, rocket)).Should(Succeed())
+			sts := &appsv1.StatefulSet{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, new(model.MongodbStatefulSetCreator).Selector(rocket), sts)
+			}, timeout, interval).Should(Succeed())
+			sts.Status.Replicas, sts.Status.ReadyReplicas = *sts.Spec.Replicas, *sts.Spec.Replicas
+			Expect(k8sClient.Status().Update(ctx, sts)).Should(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, runtimeClient.ObjectKeyFromObject(rocket), rocket); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)
+			}, timeout, interval).Should(BeTrue())
+
+			By("Starting the backup job once the database is ready")
+			// the pending backup is only requeued after the RequeueDelay, an update triggers the reconciliation right away
+			backup.Annotations = map[string]string{"test": "database-ready"}
+			Expect(k8sClient.Update(ctx, backup)).Should(Succeed())
+			Eventually(backupPhase(ctx, backup), timeout, interval).Should(Equal(chatv1alpha1.BackupPhaseRunning))
+			Expect(k8sClient.Get(ctx, jobKey, job)).Should(Succeed())
+			Expect(backup.Status.StartTime).ShouldNot(BeNil())
+			Expect(backup.Status.RocketVersion).Should(Equal(model.RocketWebserverDefaultVersion))
+			Expect(backup.Status.DatabaseVersion).Should(Equal(model.MongodbDefaultVersion))
+
+			By("Reading the result of the completed job")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      job.Name + "-pod",
+					Namespace: RocketNamespace,
+					Labels:    map[string]string{"job-name": job.Name},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{{Name: "backup", Image: "backup"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "backup",
+				Image: "backup",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Message:    `{"archive": "test-backup-completed.archive.gz", "size": 2048}`,
+					FinishedAt: metav1.Now(),
+				}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+			finishJob(ctx, backup, batchv1.JobComplete, "")
+			Eventually(backupPhase(ctx, backup), timeout, interval).Should(Equal(chatv1alpha1.BackupPhaseCompleted))
+			Expect(backup.Status.Archive).Should(Equal("test-backup-completed.archive.gz"))
+			Expect(backup.Status.Size.Value()).Should(Equal(int64(2048)))
+			Expect(backup.Status.CompletionTime).ShouldNot(BeNil())
+			Expect(backup.Status.Duration).ShouldNot(BeNil())
+			Expect(backup.Status.Duration.Duration).Should(BeNumerically(">=", time.Minute))
+
+			By("Reporting a failed job")
+			failed := createBackup(ctx, "test-backup-failed")
+			Eventually(backupPhase(ctx, failed), timeout, interval).Should(Equal(chatv1alpha1.BackupPhaseRunning))
+			finishJob(ctx, failed, batchv1.JobFailed, "Job has reached the specified backoff limit")
+			Eventually(backupPhase(ctx, failed), timeout, interval).Should(Equal(chatv1alpha1.BackupPhaseFailed))
+			Expect(failed.Status.Message).Should(ContainSubstring("backoff limit"))
+			Expect(failed.Status.Size).Should(BeNil())
+			Expect(failed.Status.Duration).ShouldNot(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var restoreLog = ctrl.Log.WithName("controllers").WithName("RocketRestore")
+
+// RocketRestoreReconciler reconciles a RocketRestore object
+type RocketRestoreReconciler struct {
+	client   runtimeClient.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+func NewRocketRestoreReconciler(client runtimeClient.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *RocketRestoreReconciler {
+	return &RocketRestoreReconciler{
+		client:   client,
+		scheme:   scheme,
+		recorder: recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketrestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketrestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=chat.accso.de,resources=rocketrestores/finalizers,verbs=update
+
+// Reconcile moves the restore through its phases: the webserver is scaled down, the mongorestore job replaces
+// the database with the archive and the webserver is scaled back to its previous replicas.
+// The Rocket isn't reconciled while the restore is active.
+func (r *RocketRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	restore := &chatv1alpha1.RocketRestore{}
+	err := r.client.Get(ctx, req.NamespacedName, restore)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !restore.IsActive() {
+		return ctrl.Result{}, nil
+	}
+
+	rocket := &chatv1alpha1.Rocket{}
+	err = r.client.Get(ctx, runtimeClient.ObjectKey{Namespace: restore.Namespace, Name: restore.Spec.Rocket}, rocket)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.manageRestoreFailure(ctx, restore, nil, fmt.Errorf("Rocket %v not found", restore.Spec.Rocket))
+		}
+		return ctrl.Result{}, err
+	}
+	model.SetRocketDefaults(rocket)
+
+	deployment := &appsv1.Deployment{}
+	err = r.client.Get(ctx, new(model.RocketDeploymentCreator).Selector(rocket), deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.manageRestoreFailure(ctx, restore, nil, fmt.Errorf("Webserver deployment of %v not found", rocket.Name))
+		}
+		return ctrl.Result{}, err
+	}
+
+	switch restore.Status.Phase {
+	case chatv1alpha1.RestorePhaseScalingDown:
+		return r.restoreDatabase(ctx, restore, rocket, deployment)
+	case chatv1alpha1.RestorePhaseRestoring:
+		return r.checkRestoreJob(ctx, restore, rocket, deployment)
+	case chatv1alpha1.RestorePhaseScalingUp:
+		return r.checkScaledUp(ctx, restore, deployment)
+	default:
+		// record the replicas before blocking the rocket, they are restored afterwards
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		now := metav1.Now()
+		restore.Status.WebserverReplicas = &replicas
+		restore.Status.StartTime = &now
+		r.recorder.Eventf(restore, "Normal", "Started", "Scaling down webserver %v to restore %v", deployment.Name, restore.Spec.Source.Archive)
+		return r.setRestorePhase(ctx, restore, chatv1alpha1.RestorePhaseScalingDown, fmt.Sprintf("Scaling down webserver %v", deployment.Name))
+	}
+}
+
+// restoreDatabase scales the webserver down and starts the restore job once all webserver pods are gone
+func (r *RocketRestoreReconciler) restoreDatabase(ctx context.Context, restore *chatv1alpha1.RocketRestore, rocket *chatv1alpha1.Rocket, deployment *appsv1.Deployment) (ctrl.Result, error) {
+	if err := r.scaleWebserver(ctx, deployment, 0); err != nil {
+		return r.manageRestoreFailure(ctx, restore, deployment, err)
+	}
+	if deployment.Status.Replicas > 0 {
+		debugLog.Info("waiting for the webserver to scale down", "object", restore.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	}
+
+	job := model.MongorestoreJob(restore, rocket)
+	if err := controllerutil.SetControllerReference(restore, job, r.scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.client.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return r.manageRestoreFailure(ctx, restore, deployment, fmt.Errorf("Error creating restore job: %w", err))
+	}
+	restoreLog.Info("started restore job", "object", restore.Name, "job", job.Name)
+	return r.setRestorePhase(ctx, restore, chatv1alpha1.RestorePhaseRestoring, fmt.Sprintf("Running restore job %v", job.Name))
+}
+
+func (r *RocketRestoreReconciler) checkRestoreJob(ctx context.Context, restore *chatv1alpha1.RocketRestore, rocket *chatv1alpha1.Rocket, deployment *appsv1.Deployment) (ctrl.Result, error) {
+	job := &batchv1.Job{}
+	err := r.client.Get(ctx, runtimeClient.ObjectKey{Namespace: restore.Namespace, Name: restore.Name + model.MongodbRestoreJobSuffix}, job)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.manageRestoreFailure(ctx, restore, deployment, fmt.Errorf("Restore job of %v not found", restore.Name))
+		}
+		return ctrl.Result{}, err
+	}
+	condition := finishedJobCondition(job)
+	if condition == nil {
+		// the job triggers a new reconciliation when it finishes
+		return ctrl.Result{}, nil
+	}
+	if condition.Type == batchv1.JobFailed {
+		message, err := jobTerminationMessage(ctx, r.client, job)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("Error reading result of job %v: %w", job.Name, err)
+		}
+		return r.manageRestoreFailure(ctx, restore, deployment, fmt.Errorf("%v: %v", condition.Message, strings.TrimSpace(message)))
+	}
+
+	if err := r.scaleWebserver(ctx, deployment, *restore.Status.WebserverReplicas); err != nil {
+		return r.manageRestoreFailure(ctx, restore, deployment, err)
+	}
+	return r.setRestorePhase(ctx, restore, chatv1alpha1.RestorePhaseScalingUp, fmt.Sprintf("Restored database of %v, scaling up webserver %v", rocket.Name, deployment.Name))
+}
+
+func (r *RocketRestoreReconciler) checkScaledUp(ctx context.Context, restore *chatv1alpha1.RocketRestore, deployment *appsv1.Deployment) (ctrl.Result, error) {
+	if deployment.Status.ReadyReplicas < *restore.Status.WebserverReplicas {
+		debugLog.Info("waiting for the webserver to scale up", "object", restore.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	}
+	now := metav1.Now()
+	restore.Status.CompletionTime = &now
+	r.recorder.Eventf(restore, "Normal", "Completed", "Restored %v from %v", restore.Spec.Rocket, restore.Spec.Source.Archive)
+	return r.setRestorePhase(ctx, restore, chatv1alpha1.RestorePhaseCompleted, fmt.Sprintf("Restored %v from %v", restore.Spec.Rocket, restore.Spec.Source.Archive))
+}
+
+// scaleWebserver sets the replicas of the webserver deployment.
+// The replicas are patched by the field manager of the operator, so the next apply of the rocket controller
+// takes them over again without reporting a conflict.
+func (r *RocketRestoreReconciler) scaleWebserver(ctx context.Context, deployment *appsv1.Deployment, replicas int32) error {
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
+		return nil
+	}
+	patch := runtimeClient.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Replicas = &replicas
+	if err := r.client.Patch(ctx, deployment, patch, runtimeClient.FieldOwner(common.FieldManager)); err != nil {
+		return fmt.Errorf("Error scaling webserver %v to %v replicas: %w", deployment.Name, replicas, err)
+	}
+	return nil
+}
+
+func (r *RocketRestoreReconciler) setRestorePhase(ctx context.Context, restore *chatv1alpha1.RocketRestore, phase chatv1alpha1.RestorePhase, message string) (ctrl.Result, error) {
+	restoreLog.Info(fmt.Sprintf("phase %v: %v", phase, message), "object", restore.Name)
+	restore.Status.Phase = phase
+	restore.Status.Message = message
+	return ctrl.Result{}, r.client.Status().Update(ctx, restore)
+}
+
+// manageRestoreFailure fails the restore and scales the webserver back up if it was scaled down
+func (r *RocketRestoreReconciler) manageRestoreFailure(ctx context.Context, restore *chatv1alpha1.RocketRestore, deployment *appsv1.Deployment, issue error) (ctrl.Result, error) {
+	restoreLog.Error(issue, "restore failed", "object", restore.Name)
+	r.recorder.Event(restore, "Warning", "Failed", issue.Error())
+
+	if deployment != nil && restore.Status.WebserverReplicas != nil {
+		if err := r.scaleWebserver(ctx, deployment, *restore.Status.WebserverReplicas); err != nil {
+			restoreLog.Error(err, "unable to scale up webserver", "object", restore.Name)
+		}
+	}
+	now := metav1.Now()
+	restore.Status.CompletionTime = &now
+	return r.setRestorePhase(ctx, restore, chatv1alpha1.RestorePhaseFailed, issue.Error())
+}
+
+// restoredRocket maps a restore to the Rocket it restores
+func restoredRocket(obj runtimeClient.Object) []reconcile.Request {
+	restore, ok := obj.(*chatv1alpha1.RocketRestore)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.Rocket}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RocketRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&chatv1alpha1.RocketRestore{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+)
+
+var _ = Describe("RocketRestore", func() {
+
+	const (
+		RocketName      = "test-rocket-restore"
+		RocketNamespace = "default"
+	)
+
+	Context("When the webserver is scaled for a restore", func() {
+		It("Should leave the replicas to the field manager of the operator", func() {
+			ctx := context.Background()
+			// the rocket isn't created, the running controller would reconcile the deployment otherwise
+			rocket := &chatv1alpha1.Rocket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      RocketName,
+					Namespace: RocketNamespace,
+					UID:       "0b7d3c51-restore-test",
+				},
+				Spec: chatv1alpha1.RocketSpec{
+					Replicas: util.CreatePointerInt32(1),
+					Database: chatv1alpha1.RocketDatabase{
+						StorageSpec: &chatv1alpha1.EmbeddedPersistentVolumeClaim{},
+					},
+				},
+			}
+			model.SetRocketDefaults(rocket)
+			creator := new(model.RocketDeploymentCreator)
+			runner := common.NewClusterActionRunner(ctx, k8sClient, scheme.Scheme, rocket)
+			Expect(runner.Update(creator.CreateResource(rocket))).Should(Succeed())
+
+			reconciler := NewRocketRestoreReconciler(k8sClient, scheme.Scheme, record.NewFakeRecorder(10))
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, creator.Selector(rocket), dep)).Should(Succeed())
+			Expect(reconciler.scaleWebserver(ctx, dep, 0)).Should(Succeed())
+			for _, entry := range dep.ManagedFields {
+				Expect(entry.Manager).Should(Equal(common.FieldManager))
+			}
+
+			runner = common.NewClusterActionRunner(ctx, k8sClient, scheme.Scheme, rocket)
+			Expect(runner.Update(creator.CreateResource(rocket))).Should(Succeed())
+			Expect(runner.Conflicts()).Should(BeEmpty())
+		})
+	})
+
+})
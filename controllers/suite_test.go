@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -53,7 +55,11 @@ var _ = BeforeSuite(func() {
 	//the envtest cluster is configured to read CRDs from the CRD directory Kubebuilder scaffolds for you.
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "config", "crd", "bases"),
+			// CRDs of other operators the controllers integrate with
+			filepath.Join("..", "config", "crd", "external"),
+		},
 		ErrorIfCRDPathMissing: true,
 	}
 
@@ -105,10 +111,19 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	rocketReconciler := NewRocketReconciler(k8sManager.GetClient(), k8sManager.GetScheme(), nil)
+	// the volume snapshot CRD is installed from config/crd/external
+	rocketReconciler := NewRocketReconciler(k8sManager.GetClient(), k8sManager.GetScheme(), k8sManager.GetEventRecorderFor("rocket-controller"), model.DefaultCompatibilityCatalog(), common.Platform{VolumeSnapshots: true})
 	err = rocketReconciler.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	backupReconciler := NewRocketBackupReconciler(k8sManager.GetClient(), k8sManager.GetScheme(), k8sManager.GetEventRecorderFor("rocketbackup-controller"))
+	err = backupReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	restoreReconciler := NewRocketRestoreReconciler(k8sManager.GetClient(), k8sManager.GetScheme(), k8sManager.GetEventRecorderFor("rocketrestore-controller"))
+	err = restoreReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	go func() {
 		err = k8sManager.Start(ctrl.SetupSignalHandler())
 		Expect(err).ToNot(HaveOccurred())
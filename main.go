@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -33,7 +34,10 @@ import (
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/controllers"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/common"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -53,11 +57,19 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var compatibilityCatalog string
+	var routerServiceAccount string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&compatibilityCatalog, "compatibility-catalog", "",
+		"Path to a yaml file with Rocket.Chat and mongodb versions supported by each other. "+
+			"Its entries replace the entries of the embedded catalog with the same version.")
+	flag.StringVar(&routerServiceAccount, "router-service-account", model.RouterNamespace+"/"+model.RouterServiceAccount,
+		"The <namespace>/<name> of the service account of the OpenShift router admitting the routes. "+
+			"It is allowed to read the certificates referenced by routes, which requires OpenShift 4.16 or newer.")
 
 	opts := zap.Options{}
 
@@ -81,11 +93,66 @@ func main() {
 		os.Exit(1)
 	}
 
-	rocketReconciler := controllers.NewRocketReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("rocket-controller"))
+	catalog, err := model.LoadCompatibilityCatalog(compatibilityCatalog)
+	if err != nil {
+		setupLog.Error(err, "unable to load compatibility catalog")
+		os.Exit(1)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	platform, err := common.DiscoverPlatform(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to discover platform")
+		os.Exit(1)
+	}
+	platform.RouterServiceAccount, err = common.ParseServiceAccount(routerServiceAccount)
+	if err != nil {
+		setupLog.Error(err, "unable to parse router service account")
+		os.Exit(1)
+	}
+	if platform.Routes {
+		setupLog.Info("OpenShift route API found, rockets are exposed with routes instead of ingresses")
+	}
+	if platform.Certificates {
+		setupLog.Info("cert-manager API found, ingress certificates can be issued")
+	}
+	if platform.HTTPRoutes {
+		setupLog.Info("Gateway API found, rockets can be exposed with HTTPRoutes")
+	}
+	if platform.VolumeSnapshots {
+		setupLog.Info("volume snapshot API found, database claims can be snapshotted on deletion")
+	}
+
+	rocketReconciler := controllers.NewRocketReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("rocket-controller"), catalog, platform)
 	if err = rocketReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Rocket")
 		os.Exit(1)
 	}
+	backupReconciler := controllers.NewRocketBackupReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("rocketbackup-controller"))
+	if err = backupReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RocketBackup")
+		os.Exit(1)
+	}
+	restoreReconciler := controllers.NewRocketRestoreReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("rocketrestore-controller"))
+	if err = restoreReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RocketRestore")
+		os.Exit(1)
+	}
+	// webhooks can be disabled when running the manager locally without certificates
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhook.SetupRocketWebhookWithManager(mgr, catalog); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Rocket")
+			os.Exit(1)
+		}
+		if err = webhook.SetupRocketBackupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RocketBackup")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
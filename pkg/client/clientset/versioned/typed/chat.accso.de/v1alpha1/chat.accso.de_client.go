@@ -27,6 +27,8 @@ import (
 type ChatV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	RocketsGetter
+	RocketRestoresGetter
+	RocketBackupsGetter
 }
 
 // ChatV1alpha1Client is used to interact with features provided by the chat.accso.de group.
@@ -38,6 +40,14 @@ func (c *ChatV1alpha1Client) Rockets(namespace string) RocketInterface {
 	return newRockets(c, namespace)
 }
 
+func (c *ChatV1alpha1Client) RocketBackups(namespace string) RocketBackupInterface {
+	return newRocketBackups(c, namespace)
+}
+
+func (c *ChatV1alpha1Client) RocketRestores(namespace string) RocketRestoreInterface {
+	return newRocketRestores(c, namespace)
+}
+
 // NewForConfig creates a new ChatV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*ChatV1alpha1Client, error) {
 	config := *c
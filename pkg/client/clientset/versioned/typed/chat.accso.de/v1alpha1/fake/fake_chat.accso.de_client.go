@@ -32,6 +32,14 @@ func (c *FakeChatV1alpha1) Rockets(namespace string) v1alpha1.RocketInterface {
 	return &FakeRockets{c, namespace}
 }
 
+func (c *FakeChatV1alpha1) RocketBackups(namespace string) v1alpha1.RocketBackupInterface {
+	return &FakeRocketBackups{c, namespace}
+}
+
+func (c *FakeChatV1alpha1) RocketRestores(namespace string) v1alpha1.RocketRestoreInterface {
+	return &FakeRocketRestores{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeChatV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Lukas Hoehl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRocketBackups implements RocketBackupInterface
+type FakeRocketBackups struct {
+	Fake *FakeChatV1alpha1
+	ns   string
+}
+
+var rocketBackupbackupsResource = schema.GroupVersionResource{Group: "chat.accso.de", Version: "v1alpha1", Resource: "rocketBackupbackups"}
+
+var rocketBackupbackupsKind = schema.GroupVersionKind{Group: "chat.accso.de", Version: "v1alpha1", Kind: "RocketBackup"}
+
+// Get takes name of the rocketBackup, and returns the corresponding rocketBackup object, and an error if there is any.
+func (c *FakeRocketBackups) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RocketBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(rocketBackupbackupsResource, c.ns, name), &v1alpha1.RocketBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketBackup), err
+}
+
+// List takes label and field selectors, and returns the list of RocketBackups that match those selectors.
+func (c *FakeRocketBackups) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RocketBackupList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(rocketBackupbackupsResource, rocketBackupbackupsKind, c.ns, opts), &v1alpha1.RocketBackupList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RocketBackupList{ListMeta: obj.(*v1alpha1.RocketBackupList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RocketBackupList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested rocketBackups.
+func (c *FakeRocketBackups) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(rocketBackupbackupsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a rocketBackup and creates it.  Returns the server's representation of the rocketBackup, and an error, if there is any.
+func (c *FakeRocketBackups) Create(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.CreateOptions) (result *v1alpha1.RocketBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(rocketBackupbackupsResource, c.ns, rocketBackup), &v1alpha1.RocketBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketBackup), err
+}
+
+// Update takes the representation of a rocketBackup and updates it. Returns the server's representation of the rocketBackup, and an error, if there is any.
+func (c *FakeRocketBackups) Update(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (result *v1alpha1.RocketBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(rocketBackupbackupsResource, c.ns, rocketBackup), &v1alpha1.RocketBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketBackup), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRocketBackups) UpdateStatus(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (*v1alpha1.RocketBackup, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(rocketBackupbackupsResource, "status", c.ns, rocketBackup), &v1alpha1.RocketBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketBackup), err
+}
+
+// Delete takes name of the rocketBackup and deletes it. Returns an error if one occurs.
+func (c *FakeRocketBackups) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(rocketBackupbackupsResource, c.ns, name), &v1alpha1.RocketBackup{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRocketBackups) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(rocketBackupbackupsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RocketBackupList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched rocketBackup.
+func (c *FakeRocketBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(rocketBackupbackupsResource, c.ns, name, pt, data, subresources...), &v1alpha1.RocketBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketBackup), err
+}
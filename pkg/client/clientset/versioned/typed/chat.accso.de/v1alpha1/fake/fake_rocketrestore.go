@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Lukas Hoehl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRocketRestores implements RocketRestoreInterface
+type FakeRocketRestores struct {
+	Fake *FakeChatV1alpha1
+	ns   string
+}
+
+var rocketRestorerestoresResource = schema.GroupVersionResource{Group: "chat.accso.de", Version: "v1alpha1", Resource: "rocketRestorerestores"}
+
+var rocketRestorerestoresKind = schema.GroupVersionKind{Group: "chat.accso.de", Version: "v1alpha1", Kind: "RocketRestore"}
+
+// Get takes name of the rocketRestore, and returns the corresponding rocketRestore object, and an error if there is any.
+func (c *FakeRocketRestores) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RocketRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(rocketRestorerestoresResource, c.ns, name), &v1alpha1.RocketRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketRestore), err
+}
+
+// List takes label and field selectors, and returns the list of RocketRestores that match those selectors.
+func (c *FakeRocketRestores) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RocketRestoreList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(rocketRestorerestoresResource, rocketRestorerestoresKind, c.ns, opts), &v1alpha1.RocketRestoreList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RocketRestoreList{ListMeta: obj.(*v1alpha1.RocketRestoreList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RocketRestoreList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested rocketRestores.
+func (c *FakeRocketRestores) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(rocketRestorerestoresResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a rocketRestore and creates it.  Returns the server's representation of the rocketRestore, and an error, if there is any.
+func (c *FakeRocketRestores) Create(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.CreateOptions) (result *v1alpha1.RocketRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(rocketRestorerestoresResource, c.ns, rocketRestore), &v1alpha1.RocketRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketRestore), err
+}
+
+// Update takes the representation of a rocketRestore and updates it. Returns the server's representation of the rocketRestore, and an error, if there is any.
+func (c *FakeRocketRestores) Update(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (result *v1alpha1.RocketRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(rocketRestorerestoresResource, c.ns, rocketRestore), &v1alpha1.RocketRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketRestore), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRocketRestores) UpdateStatus(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (*v1alpha1.RocketRestore, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(rocketRestorerestoresResource, "status", c.ns, rocketRestore), &v1alpha1.RocketRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketRestore), err
+}
+
+// Delete takes name of the rocketRestore and deletes it. Returns an error if one occurs.
+func (c *FakeRocketRestores) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(rocketRestorerestoresResource, c.ns, name), &v1alpha1.RocketRestore{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRocketRestores) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(rocketRestorerestoresResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RocketRestoreList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched rocketRestore.
+func (c *FakeRocketRestores) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(rocketRestorerestoresResource, c.ns, name, pt, data, subresources...), &v1alpha1.RocketRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RocketRestore), err
+}
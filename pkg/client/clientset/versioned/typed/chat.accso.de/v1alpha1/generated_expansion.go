@@ -19,3 +19,7 @@ limitations under the License.
 package v1alpha1
 
 type RocketExpansion interface{}
+
+type RocketBackupExpansion interface{}
+
+type RocketRestoreExpansion interface{}
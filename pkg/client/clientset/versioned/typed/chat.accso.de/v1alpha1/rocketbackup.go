@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Lukas Hoehl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	scheme "github.com/bachelor-thesis-hown3d/chat-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RocketBackupsGetter has a method to return a RocketBackupInterface.
+// A group's client should implement this interface.
+type RocketBackupsGetter interface {
+	RocketBackups(namespace string) RocketBackupInterface
+}
+
+// RocketBackupInterface has methods to work with RocketBackup resources.
+type RocketBackupInterface interface {
+	Create(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.CreateOptions) (*v1alpha1.RocketBackup, error)
+	Update(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (*v1alpha1.RocketBackup, error)
+	UpdateStatus(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (*v1alpha1.RocketBackup, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.RocketBackup, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RocketBackupList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketBackup, err error)
+	RocketBackupExpansion
+}
+
+// rocketBackups implements RocketBackupInterface
+type rocketBackups struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRocketBackups returns a RocketBackups
+func newRocketBackups(c *ChatV1alpha1Client, namespace string) *rocketBackups {
+	return &rocketBackups{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the rocketBackup, and returns the corresponding rocketBackup object, and an error if there is any.
+func (c *rocketBackups) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RocketBackup, err error) {
+	result = &v1alpha1.RocketBackup{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RocketBackups that match those selectors.
+func (c *rocketBackups) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RocketBackupList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RocketBackupList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested rocketBackups.
+func (c *rocketBackups) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a rocketBackup and creates it.  Returns the server's representation of the rocketBackup, and an error, if there is any.
+func (c *rocketBackups) Create(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.CreateOptions) (result *v1alpha1.RocketBackup, err error) {
+	result = &v1alpha1.RocketBackup{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a rocketBackup and updates it. Returns the server's representation of the rocketBackup, and an error, if there is any.
+func (c *rocketBackups) Update(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (result *v1alpha1.RocketBackup, err error) {
+	result = &v1alpha1.RocketBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		Name(rocketBackup.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *rocketBackups) UpdateStatus(ctx context.Context, rocketBackup *v1alpha1.RocketBackup, opts v1.UpdateOptions) (result *v1alpha1.RocketBackup, err error) {
+	result = &v1alpha1.RocketBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		Name(rocketBackup.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the rocketBackup and deletes it. Returns an error if one occurs.
+func (c *rocketBackups) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *rocketBackups) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched rocketBackup.
+func (c *rocketBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketBackup, err error) {
+	result = &v1alpha1.RocketBackup{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("rocketBackupbackups").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
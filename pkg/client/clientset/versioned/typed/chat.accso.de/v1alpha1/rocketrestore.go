@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Lukas Hoehl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	scheme "github.com/bachelor-thesis-hown3d/chat-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RocketRestoresGetter has a method to return a RocketRestoreInterface.
+// A group's client should implement this interface.
+type RocketRestoresGetter interface {
+	RocketRestores(namespace string) RocketRestoreInterface
+}
+
+// RocketRestoreInterface has methods to work with RocketRestore resources.
+type RocketRestoreInterface interface {
+	Create(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.CreateOptions) (*v1alpha1.RocketRestore, error)
+	Update(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (*v1alpha1.RocketRestore, error)
+	UpdateStatus(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (*v1alpha1.RocketRestore, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.RocketRestore, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RocketRestoreList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketRestore, err error)
+	RocketRestoreExpansion
+}
+
+// rocketRestores implements RocketRestoreInterface
+type rocketRestores struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRocketRestores returns a RocketRestores
+func newRocketRestores(c *ChatV1alpha1Client, namespace string) *rocketRestores {
+	return &rocketRestores{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the rocketRestore, and returns the corresponding rocketRestore object, and an error if there is any.
+func (c *rocketRestores) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RocketRestore, err error) {
+	result = &v1alpha1.RocketRestore{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RocketRestores that match those selectors.
+func (c *rocketRestores) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RocketRestoreList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RocketRestoreList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested rocketRestores.
+func (c *rocketRestores) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a rocketRestore and creates it.  Returns the server's representation of the rocketRestore, and an error, if there is any.
+func (c *rocketRestores) Create(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.CreateOptions) (result *v1alpha1.RocketRestore, err error) {
+	result = &v1alpha1.RocketRestore{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketRestore).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a rocketRestore and updates it. Returns the server's representation of the rocketRestore, and an error, if there is any.
+func (c *rocketRestores) Update(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (result *v1alpha1.RocketRestore, err error) {
+	result = &v1alpha1.RocketRestore{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		Name(rocketRestore.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketRestore).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *rocketRestores) UpdateStatus(ctx context.Context, rocketRestore *v1alpha1.RocketRestore, opts v1.UpdateOptions) (result *v1alpha1.RocketRestore, err error) {
+	result = &v1alpha1.RocketRestore{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		Name(rocketRestore.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(rocketRestore).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the rocketRestore and deletes it. Returns an error if one occurs.
+func (c *rocketRestores) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *rocketRestores) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched rocketRestore.
+func (c *rocketRestores) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RocketRestore, err error) {
+	result = &v1alpha1.RocketRestore{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("rocketRestorerestores").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
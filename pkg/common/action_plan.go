@@ -0,0 +1,101 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// actionPlan is a stable topological order of planned actions.
+// Every step only depends on steps with a lower index.
+type actionPlan struct {
+	steps []planStep
+}
+
+type planStep struct {
+	plannedAction
+	// indices of the steps this step depends on
+	dependsOn []int
+}
+
+// newActionPlan orders the actions by the dependencies of their creators.
+// Dependencies on creators without an action are already satisfied and ignored.
+// Independent actions keep the order in which they were planned, so the same actions always result in the same plan.
+func newActionPlan(actions []plannedAction) (*actionPlan, error) {
+	index := make(map[string]int, len(actions))
+	for i, action := range actions {
+		name := action.creator.Name()
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("Error planning actions: creator name %v is not unique", name)
+		}
+		index[name] = i
+	}
+
+	// dependencies and dependents by the index of the action
+	dependencies := make([][]int, len(actions))
+	dependents := make([][]int, len(actions))
+	for i, action := range actions {
+		for _, dependency := range action.creator.DependsOn() {
+			j, ok := index[dependency.Name()]
+			if !ok {
+				continue
+			}
+			dependencies[i] = append(dependencies[i], j)
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	// Kahn's algorithm, always picking the ready action that was planned first
+	remaining := make([]int, len(actions))
+	for i := range actions {
+		remaining[i] = len(dependencies[i])
+	}
+	position := make([]int, len(actions))
+	done := make([]bool, len(actions))
+	plan := &actionPlan{}
+	for len(plan.steps) < len(actions) {
+		next := -1
+		for i := range actions {
+			if !done[i] && remaining[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("Error planning actions: dependency cycle between %v", pendingNames(actions, done))
+		}
+		done[next] = true
+		position[next] = len(plan.steps)
+		for _, dependent := range dependents[next] {
+			remaining[dependent]--
+		}
+		step := planStep{plannedAction: actions[next]}
+		for _, dependency := range dependencies[next] {
+			step.dependsOn = append(step.dependsOn, position[dependency])
+		}
+		plan.steps = append(plan.steps, step)
+	}
+	return plan, nil
+}
+
+func pendingNames(actions []plannedAction, done []bool) string {
+	var names []string
+	for i, action := range actions {
+		if !done[i] {
+			names = append(names, action.creator.Name())
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// String returns a reproducible description of the plan
+func (p *actionPlan) String() string {
+	var steps []string
+	for i, step := range p.steps {
+		description := fmt.Sprintf("(%d) %s", i, step.String())
+		if len(step.dependsOn) > 0 {
+			description += fmt.Sprintf(" after %v", step.dependsOn)
+		}
+		steps = append(steps, description)
+	}
+	return strings.Join(steps, "; ")
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+)
+
+// cyclicCreator depends on itself
+type cyclicCreator struct {
+	model.RocketServiceCreator
+}
+
+func (c *cyclicCreator) DependsOn() []model.ResourceCreator {
+	return []model.ResourceCreator{c}
+}
+
+func planned(creators ...model.ResourceCreator) []plannedAction {
+	var actions []plannedAction
+	for _, creator := range creators {
+		actions = append(actions, plannedAction{
+			ClusterAction: GenericCreateAction{Msg: "Create " + creator.Name()},
+			creator:       creator,
+		})
+	}
+	return actions
+}
+
+func planOrder(t *testing.T, actions []plannedAction) []string {
+	plan, err := newActionPlan(actions)
+	if err != nil {
+		t.Fatalf("newActionPlan() error = %v", err)
+	}
+	var names []string
+	for _, step := range plan.steps {
+		names = append(names, step.creator.Name())
+	}
+	return names
+}
+
+func TestNewActionPlan(t *testing.T) {
+	actions := planned(
+		new(model.RocketIngressCreator),
+		new(model.MongodbStatefulSetCreator),
+		new(model.RocketDeploymentCreator),
+		&model.MongodbServiceCreator{Headless: true},
+		new(model.MongodbScriptsConfigmapCreator),
+		new(model.ServiceAccountCreator),
+		new(model.RocketServiceCreator),
+		new(model.MongodbAuthSecretCreator),
+		&model.MongodbServiceCreator{Headless: false},
+		new(model.RocketAdminSecretCreator),
+	)
+	order := planOrder(t, actions)
+	position := map[string]int{}
+	for i, name := range order {
+		position[name] = i
+	}
+	for _, action := range actions {
+		for _, dependency := range action.creator.DependsOn() {
+			if position[dependency.Name()] > position[action.creator.Name()] {
+				t.Errorf("%v is planned before its dependency %v: %v", action.creator.Name(), dependency.Name(), order)
+			}
+		}
+	}
+
+	// the same actions always result in the same plan
+	for i := 0; i < 10; i++ {
+		again := planOrder(t, actions)
+		for j := range order {
+			if order[j] != again[j] {
+				t.Fatalf("plan is not stable: %v != %v", order, again)
+			}
+		}
+	}
+}
+
+func TestNewActionPlanIgnoresSatisfiedDependencies(t *testing.T) {
+	order := planOrder(t, planned(new(model.RocketIngressCreator)))
+	if len(order) != 1 {
+		t.Errorf("expected a single step, got %v", order)
+	}
+}
+
+func TestNewActionPlanCycle(t *testing.T) {
+	if _, err := newActionPlan(planned(new(cyclicCreator))); err == nil {
+		t.Errorf("expected an error for a dependency cycle")
+	}
+}
+
+func TestNewActionPlanDuplicateNames(t *testing.T) {
+	if _, err := newActionPlan(planned(new(model.RocketServiceCreator), new(model.RocketServiceCreator))); err == nil {
+		t.Errorf("expected an error for duplicate creator names")
+	}
+}
@@ -3,9 +3,13 @@ package common
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/go-logr/logr"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -15,8 +19,13 @@ import (
 
 var actionLogger = ctrl.Log.WithName("actions").WithName("Rocket")
 
+// FieldManager is the field manager of all resources applied by the operator
+const FieldManager = "chat-operator"
+
 type ClusterAction interface {
 	Run(runner *ClusterActionRunner) (string, error)
+	// String describes the action for the action plan
+	String() string
 }
 
 type ClusterActionRunner struct {
@@ -25,6 +34,14 @@ type ClusterActionRunner struct {
 	scheme  *runtime.Scheme
 	parent  runtimeClient.Object
 	log     *logr.Logger
+	// messages of the actions that ran successfully
+	applied []string
+
+	mu sync.Mutex
+	// conflicts with other field managers, actions run in parallel
+	conflicts []string
+	// resources the read state missed, they were created after the cache was synced
+	outdated []string
 }
 
 // Create an action runner to run kubernetes actions
@@ -37,40 +54,181 @@ func NewClusterActionRunner(context context.Context, client runtimeClient.Client
 	}
 }
 
+// RunAll runs the actions of the desired state in the order of their dependencies.
+// Every action waits for the actions it depends on, independent actions run in parallel.
+// Actions depending on a failed action are skipped.
 func (runner *ClusterActionRunner) RunAll(desiredState *desiredClusterState) error {
-	for index, action := range desiredState.actions {
-		msg, err := action.Run(runner)
-		if err != nil {
-			actionLogger.Info(fmt.Sprintf("(%5d) %10s %s : %s", index, "FAILED", msg, err))
-			return err
-		}
-		actionLogger.Info(fmt.Sprintf("(%5d) %10s %s", index, "SUCCESS", msg), "object", runner.parent.GetName())
+	plan, err := newActionPlan(desiredState.actions)
+	if err != nil {
+		return err
+	}
+	if len(plan.steps) == 0 {
+		return nil
+	}
+	actionLogger.Info(fmt.Sprintf("action plan: %v", plan), "object", runner.parent.GetName())
+
+	var wg sync.WaitGroup
+	// every step only writes its own index
+	done := make([]chan struct{}, len(plan.steps))
+	failed := make([]bool, len(plan.steps))
+	msgs := make([]string, len(plan.steps))
+	errs := make([]error, len(plan.steps))
+	for index := range plan.steps {
+		done[index] = make(chan struct{})
 	}
 
+	for index, step := range plan.steps {
+		wg.Add(1)
+		go func(index int, step planStep) {
+			defer wg.Done()
+			// failed[index] is written before done[index] is closed, so dependents can read it afterwards
+			defer close(done[index])
+
+			for _, dependency := range step.dependsOn {
+				<-done[dependency]
+			}
+			for _, dependency := range step.dependsOn {
+				if failed[dependency] {
+					failed[index] = true
+					actionLogger.Info(fmt.Sprintf("(%5d) %10s %s", index, "SKIPPED", step), "object", runner.parent.GetName())
+					return
+				}
+			}
+
+			msg, err := step.Run(runner)
+			if err != nil {
+				failed[index] = true
+				errs[index] = err
+				actionLogger.Info(fmt.Sprintf("(%5d) %10s %s : %s", index, "FAILED", msg, err), "object", runner.parent.GetName())
+				return
+			}
+			actionLogger.Info(fmt.Sprintf("(%5d) %10s %s", index, "SUCCESS", msg), "object", runner.parent.GetName())
+			msgs[index] = msg
+		}(index, step)
+	}
+	wg.Wait()
+
+	// collect results in the order of the plan
+	var errMsgs []string
+	for index := range plan.steps {
+		if errs[index] != nil {
+			errMsgs = append(errMsgs, errs[index].Error())
+		} else if msgs[index] != "" {
+			runner.applied = append(runner.applied, msgs[index])
+		}
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errMsgs, "; "))
+	}
 	return nil
 }
 
+// Applied returns the messages of the actions that ran successfully
+func (runner *ClusterActionRunner) Applied() []string {
+	return runner.applied
+}
+
+// Create creates the resource if it doesn't exist yet.
+// A resource that exists although the read state missed it is left untouched, a create must never overwrite
+// generated data like the credentials of the database. The outdated read is recorded, so the caller can read the state again.
 func (runner *ClusterActionRunner) Create(obj runtimeClient.Object) error {
-	err := controllerutil.SetControllerReference(runner.parent.(metav1.Object), obj.(metav1.Object), runner.scheme)
-	if err != nil {
-		return fmt.Errorf("Error setting controller owner reference on resource %v to owner %v: %w", obj.GetName(), runner.parent.GetName(), err)
+	if err := runner.setOwner(obj); err != nil {
+		return err
+	}
+	err := runner.client.Create(runner.context, obj, runtimeClient.FieldOwner(FieldManager))
+	if apiErrors.IsAlreadyExists(err) {
+		runner.mu.Lock()
+		runner.outdated = append(runner.outdated, obj.GetName())
+		runner.mu.Unlock()
+		return nil
 	}
-
-	err = runner.client.Create(runner.context, obj)
 	if err != nil {
 		return fmt.Errorf("Error creating resource %v: %w", obj.GetName(), err)
 	}
-
 	return nil
 }
 
 func (runner *ClusterActionRunner) Update(obj runtimeClient.Object) error {
+	if err := runner.apply(obj); err != nil {
+		return fmt.Errorf("Error updating resource %v: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+func (runner *ClusterActionRunner) Delete(obj runtimeClient.Object) error {
+	// dependents like the pods of a deployment are removed by the garbage collector
+	err := runner.client.Delete(runner.context, obj, runtimeClient.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting resource %v: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// Conflicts returns the field manager conflicts the runner had to force its way through
+func (runner *ClusterActionRunner) Conflicts() []string {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	return runner.conflicts
+}
+
+// Outdated returns the resources that existed although the read state missed them
+func (runner *ClusterActionRunner) Outdated() []string {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	return runner.outdated
+}
+
+func (runner *ClusterActionRunner) setOwner(obj runtimeClient.Object) error {
 	err := controllerutil.SetControllerReference(runner.parent.(metav1.Object), obj.(metav1.Object), runner.scheme)
+	if err != nil {
+		return fmt.Errorf("Error setting controller owner reference on resource %v to owner %v: %w", obj.GetName(), runner.parent.GetName(), err)
+	}
+	return nil
+}
+
+// apply applies the fields set in obj with server-side apply.
+// Fields owned by other field managers that conflict with obj are recorded and taken over with a forced apply,
+// fields not set in obj are left untouched.
+func (runner *ClusterActionRunner) apply(obj runtimeClient.Object) error {
+	if err := runner.setOwner(obj); err != nil {
+		return err
+	}
+	// apply requests need the type information and must not contain server side metadata
+	gvk, err := apiutil.GVKForObject(obj, runner.scheme)
 	if err != nil {
 		return err
 	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
 
-	return runner.client.Update(runner.context, obj)
+	err = runner.client.Patch(runner.context, obj, runtimeClient.Apply, runtimeClient.FieldOwner(FieldManager))
+	if apiErrors.IsConflict(err) {
+		if !isOwnCreateConflict(err) {
+			runner.mu.Lock()
+			runner.conflicts = append(runner.conflicts, fmt.Sprintf("%v %v: %v", gvk.Kind, obj.GetName(), err))
+			runner.mu.Unlock()
+		}
+		err = runner.client.Patch(runner.context, obj, runtimeClient.Apply, runtimeClient.FieldOwner(FieldManager), runtimeClient.ForceOwnership)
+	}
+	return err
+}
+
+// isOwnCreateConflict returns true if all fields of the conflict belong to the operator itself.
+// Resources created by Create are owned by the update operation of the field manager,
+// which conflicts with its apply operation when a field changes.
+func isOwnCreateConflict(err error) bool {
+	status, ok := err.(apiErrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	causes := status.Status().Details.Causes
+	for _, cause := range causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict || !strings.HasPrefix(cause.Message, fmt.Sprintf("conflict with %q using ", FieldManager)) {
+			return false
+		}
+	}
+	return len(causes) > 0
 }
 
 // An action to create generic kubernetes resources
@@ -87,6 +245,12 @@ type GenericUpdateAction struct {
 	Msg string
 }
 
+// An action to delete generic kubernetes resources which aren't wanted anymore
+type GenericDeleteAction struct {
+	runtimeClient.Object
+	Msg string
+}
+
 func (action GenericCreateAction) Run(runner *ClusterActionRunner) (string, error) {
 	return action.Msg, runner.Create(action.Object)
 }
@@ -94,3 +258,19 @@ func (action GenericCreateAction) Run(runner *ClusterActionRunner) (string, erro
 func (action GenericUpdateAction) Run(runner *ClusterActionRunner) (string, error) {
 	return action.Msg, runner.Update(action.Object)
 }
+
+func (action GenericCreateAction) String() string {
+	return action.Msg
+}
+
+func (action GenericUpdateAction) String() string {
+	return action.Msg
+}
+
+func (action GenericDeleteAction) Run(runner *ClusterActionRunner) (string, error) {
+	return action.Msg, runner.Delete(action.Object)
+}
+
+func (action GenericDeleteAction) String() string {
+	return action.Msg
+}
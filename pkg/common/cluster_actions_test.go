@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreateKeepsExistingResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := chatv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	rocket := &chatv1alpha1.Rocket{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "1234"}}
+	creator := new(model.MongodbAuthSecretCreator)
+	live := creator.CreateResource(rocket).(*corev1.Secret)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(live).Build()
+
+	// the read state missed the secret, the create must not replace its credentials
+	runner := NewClusterActionRunner(context.TODO(), client, scheme, rocket)
+	if err := runner.Create(creator.CreateResource(rocket)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if outdated := runner.Outdated(); len(outdated) != 1 || outdated[0] != live.Name {
+		t.Errorf("Outdated() = %v, want [%v]", outdated, live.Name)
+	}
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), creator.Selector(rocket), secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["password"]) != string(live.Data["password"]) {
+		t.Errorf("Create() replaced the password of the existing secret")
+	}
+}
+
+// fieldManagerConflict returns the conflict of an apply with the field managers of the messages
+func fieldManagerConflict(messages ...string) error {
+	err := apiErrors.NewConflict(schema.GroupResource{Resource: "deployments"}, "test", nil)
+	err.ErrStatus.Details.Causes = nil
+	for _, message := range messages {
+		err.ErrStatus.Details.Causes = append(err.ErrStatus.Details.Causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: message,
+			Field:   ".spec.replicas",
+		})
+	}
+	return err
+}
+
+// conflictingClient rejects applies which don't force the ownership with its conflict, the fake client doesn't support apply patches
+type conflictingClient struct {
+	runtimeClient.Client
+	conflict error
+	forced   bool
+}
+
+func (c *conflictingClient) Patch(ctx context.Context, obj runtimeClient.Object, patch runtimeClient.Patch, opts ...runtimeClient.PatchOption) error {
+	options := (&runtimeClient.PatchOptions{}).ApplyOptions(opts)
+	if options.Force == nil || !*options.Force {
+		return c.conflict
+	}
+	c.forced = true
+	return nil
+}
+
+func TestApplyForcesConflicts(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := chatv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	rocket := &chatv1alpha1.Rocket{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "1234"}}
+	tests := []struct {
+		name          string
+		conflict      error
+		wantConflicts int
+	}{
+		{name: "other manager", conflict: fieldManagerConflict(`conflict with "kubectl-edit" using apps/v1`), wantConflicts: 1},
+		{name: "own create", conflict: fieldManagerConflict(`conflict with "chat-operator" using apps/v1`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &conflictingClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), conflict: tt.conflict}
+			runner := NewClusterActionRunner(context.TODO(), client, scheme, rocket)
+			if err := runner.Update(new(model.RocketServiceCreator).CreateResource(rocket)); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if !client.forced {
+				t.Errorf("Update() didn't force the apply")
+			}
+			conflicts := runner.Conflicts()
+			if len(conflicts) != tt.wantConflicts {
+				t.Fatalf("Conflicts() = %v, want %v conflicts", conflicts, tt.wantConflicts)
+			}
+			if tt.wantConflicts > 0 && !strings.Contains(conflicts[0], "Service test-rocketchat-service") {
+				t.Errorf("Conflicts() = %v, want the conflicting service", conflicts)
+			}
+		})
+	}
+}
+
+func TestIsOwnCreateConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "own create", err: fieldManagerConflict(`conflict with "chat-operator" using apps/v1`), want: true},
+		{name: "other manager", err: fieldManagerConflict(`conflict with "kubectl-edit" using apps/v1`)},
+		{name: "own create and other manager", err: fieldManagerConflict(`conflict with "chat-operator" using apps/v1`, `conflict with "hpa"`)},
+		{name: "no causes", err: fieldManagerConflict()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOwnCreateConflict(tt.err); got != tt.want {
+				t.Errorf("isOwnCreateConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,61 +3,116 @@ package common
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 )
 
 type ClusterStateReader struct {
-	// key is Name of the Object, value wether is exists
+	// creators in the order they were added, determines the order of the planned actions
+	creators []model.ResourceCreator
+	// key is the creator of the object, value the object in the cluster or nil if it doesn't exist
 	state    map[model.ResourceCreator]runtimeClient.Object
 	client   runtimeClient.Client
 	ctx      context.Context
 	instance *chatv1alpha1.Rocket
+	platform Platform
 }
 
 // NewCurrentStateReader creates a new CurrentStateReader with its state attached
 // state map wil be initialized with the resourceCreators and nil pointers to the resources
-// the Order of the resourceCreators inside the state will determine, in which order the resources will be created
-func NewCurrentStateReader(ctx context.Context, client runtimeClient.Client, rocket *chatv1alpha1.Rocket) (*ClusterStateReader, error) {
+// the order in which the creators are added is used to order independent actions reproducibly,
+// the order of creation is determined by the dependencies of the creators
+func NewCurrentStateReader(ctx context.Context, client runtimeClient.Client, rocket *chatv1alpha1.Rocket, platform Platform) (*ClusterStateReader, error) {
 	reader := &ClusterStateReader{
 		client:   client,
 		instance: rocket,
 		ctx:      ctx,
+		state:    map[model.ResourceCreator]runtimeClient.Object{},
+		platform: platform,
 	}
-	mongodbStsCreator := new(model.MongodbStatefulSetCreator)
-	reader.state = map[model.ResourceCreator]runtimeClient.Object{
-		new(model.ServiceAccountCreator):              nil,
-		new(model.MongodbAuthSecretCreator):           nil,
-		new(model.MongodbScriptsConfigmapCreator):     nil,
-		&model.MongodbServiceCreator{Headless: false}: nil,
-		&model.MongodbServiceCreator{Headless: true}:  nil,
-		mongodbStsCreator:                             nil,
-	}
-
-	ready, err := reader.isStatefulSetReady(mongodbStsCreator, rocket)
+	reader.add(new(model.ServiceAccountCreator))
+	ready, err := reader.addDatabase(rocket)
 	if err != nil {
-		return nil, fmt.Errorf("Error determining wether statefulSet %v is ready: %w", mongodbStsCreator.Name(), err)
+		return nil, err
 	}
 	if ready {
-		rocketState := map[model.ResourceCreator]runtimeClient.Object{
-			new(model.RocketAdminSecretCreator): nil,
-			new(model.RocketDeploymentCreator):  nil,
-			new(model.RocketServiceCreator):     nil,
-			new(model.RocketIngressCreator):     nil,
-		}
-		// merge into state map
-		for k, v := range rocketState {
-			reader.state[k] = v
-		}
+		reader.add(
+			new(model.RocketAdminSecretCreator),
+			new(model.RocketDeploymentCreator),
+			new(model.RocketServiceCreator),
+		)
+		reader.addExposure()
+		reader.add(new(model.MongodbBackupCronJobCreator))
 	}
 	return reader, nil
 }
+
+// addDatabase adds the creators of the database and returns true if the database is ready.
+// For an external database only the job checking its connectivity is created.
+func (c *ClusterStateReader) addDatabase(rocket *chatv1alpha1.Rocket) (bool, error) {
+	if rocket.HasExternalDatabase() {
+		checkJobCreator := new(model.MongodbCheckJobCreator)
+		c.add(checkJobCreator)
+		ready, err := c.isDatabaseReachable(checkJobCreator, rocket)
+		if err != nil {
+			return false, fmt.Errorf("Error determining wether the external database is reachable: %w", err)
+		}
+		return ready, nil
+	}
+
+	mongodbStsCreator := new(model.MongodbStatefulSetCreator)
+	c.add(
+		new(model.MongodbAuthSecretCreator),
+		new(model.MongodbScriptsConfigmapCreator),
+		&model.MongodbServiceCreator{Headless: false},
+		&model.MongodbServiceCreator{Headless: true},
+		mongodbStsCreator,
+	)
+	ready, err := c.isStatefulSetReady(mongodbStsCreator, rocket)
+	if err != nil {
+		return false, fmt.Errorf("Error determining wether statefulSet %v is ready: %w", mongodbStsCreator.Name(), err)
+	}
+	return ready, nil
+}
+
+// addExposure adds the creators of the resources exposing Rocket.Chat outside of the cluster.
+// The creators of all exposures the cluster supports are added, the resources of the exposures which aren't used are deleted.
+// Resources of APIs the cluster doesn't serve are never read, every read would rediscover the APIs of the cluster.
+// On OpenShift the ingress is replaced by a route.
+func (c *ClusterStateReader) addExposure() {
+	c.add(&model.RocketIngressCreator{ReplacedByRoute: c.platform.Routes})
+	if c.platform.Certificates {
+		c.add(new(model.RocketCertificateCreator))
+	}
+	if c.platform.HTTPRoutes {
+		c.add(new(model.RocketHTTPRouteCreator))
+	}
+	if c.platform.Routes {
+		c.add(
+			new(model.RouteCertificateRoleCreator),
+			&model.RouteCertificateRoleBindingCreator{Router: c.platform.RouterServiceAccount},
+			new(model.RouteCreator),
+		)
+	}
+}
+
+// add adds the creators to the state, their resources are not read yet
+func (c *ClusterStateReader) add(creators ...model.ResourceCreator) {
+	for _, creator := range creators {
+		c.creators = append(c.creators, creator)
+		c.state[creator] = nil
+	}
+}
+
 func (c *ClusterStateReader) Read() error {
-	for creator := range c.state {
+	for _, creator := range c.creators {
 		err := c.readObjectState(creator)
 		if err != nil {
 			return fmt.Errorf("Error reading object State from creator %v: %w", creator.Name(), err)
@@ -70,13 +125,14 @@ func (c *ClusterStateReader) Read() error {
 func (c *ClusterStateReader) readObjectState(
 	resourceCreator model.ResourceCreator,
 ) error {
-	resource := resourceCreator.CreateResource(c.instance)
+	// read into an empty object, so no desired fields are left over in the live resource
+	resource := newEmptyObject(resourceCreator.CreateResource(c.instance))
 	selector := resourceCreator.Selector(c.instance)
 	err := c.client.Get(c.ctx, selector, resource)
 
 	if err != nil {
-		// If the resource is not found
-		if apiErrors.IsNotFound(err) {
+		// If the resource is not found or its api, like the one of cert-manager, isn't installed
+		if apiErrors.IsNotFound(err) || meta.IsNoMatchError(err) {
 			// set state of the resource to nil, doesnt exists or no match
 			c.state[resourceCreator] = nil
 			return nil
@@ -87,3 +143,14 @@ func (c *ClusterStateReader) readObjectState(
 	c.state[resourceCreator] = resource
 	return nil
 }
+
+// newEmptyObject returns an empty object of the same type as obj,
+// unstructured objects keep their type information so the client knows which resource to read
+func newEmptyObject(obj runtimeClient.Object) runtimeClient.Object {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		empty := &unstructured.Unstructured{}
+		empty.SetGroupVersionKind(u.GroupVersionKind())
+		return empty
+	}
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtimeClient.Object)
+}
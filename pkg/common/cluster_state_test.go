@@ -0,0 +1,51 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAddExposure(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform Platform
+		want     []string
+	}{
+		{name: "plain cluster", want: []string{new(model.RocketIngressCreator).Name()}},
+		{
+			name:     "cert-manager and Gateway API",
+			platform: Platform{Certificates: true, HTTPRoutes: true},
+			want:     []string{new(model.RocketIngressCreator).Name(), new(model.RocketCertificateCreator).Name(), new(model.RocketHTTPRouteCreator).Name()},
+		},
+		{
+			name:     "OpenShift",
+			platform: Platform{Routes: true},
+			want: []string{
+				new(model.RocketIngressCreator).Name(),
+				new(model.RouteCertificateRoleCreator).Name(),
+				new(model.RouteCertificateRoleBindingCreator).Name(),
+				new(model.RouteCreator).Name(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &ClusterStateReader{state: map[model.ResourceCreator]runtimeClient.Object{}, platform: tt.platform}
+			reader.addExposure()
+			var got []string
+			for _, creator := range reader.creators {
+				got = append(got, creator.Name())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("addExposure() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("addExposure() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -5,42 +5,85 @@ import (
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // The desired cluster state is defined by a list of actions that have to be run to
 // get from the current state to the desired state
 type desiredClusterState struct {
-	actions []ClusterAction
+	actions []plannedAction
 }
 
-// NewDesiredState creates a new DesiredState regarding the clusterState
-func NewDesiredState(clusterState *ClusterStateReader, rocket *chatv1alpha1.Rocket) *desiredClusterState {
+// plannedAction is an action together with the creator of the resource it acts on
+type plannedAction struct {
+	ClusterAction
+	creator model.ResourceCreator
+	// fields of the live resource that drifted from the desired state
+	drifted []string
+}
+
+// ResourceDrift contains the drifted fields of a resource
+type ResourceDrift struct {
+	Name   string
+	Fields []string
+}
+
+// NewDesiredState creates a new DesiredState regarding the clusterState.
+// The overrides of the rocket are applied to the desired resources before they are compared with the cluster.
+func NewDesiredState(clusterState *ClusterStateReader, rocket *chatv1alpha1.Rocket) (*desiredClusterState, error) {
 	desired := &desiredClusterState{}
-	for creator, resource := range clusterState.state {
-		action := getObjectDesiredState(rocket, resource, creator)
+	for _, creator := range clusterState.creators {
+		action, drifted, err := getObjectDesiredState(rocket, clusterState.state[creator], creator)
+		if err != nil {
+			return nil, err
+		}
 		if action != nil {
-			desired.actions = append(desired.actions, action)
+			desired.actions = append(desired.actions, plannedAction{ClusterAction: action, creator: creator, drifted: drifted})
+		}
+	}
+	return desired, nil
+}
+
+// Drift returns the drifted fields of all resources that need to be updated
+func (d *desiredClusterState) Drift() []ResourceDrift {
+	var drift []ResourceDrift
+	for _, action := range d.actions {
+		if len(action.drifted) > 0 {
+			drift = append(drift, ResourceDrift{Name: action.creator.Name(), Fields: action.drifted})
 		}
 	}
-	return desired
+	return drift
 }
 
-func getObjectDesiredState(rocket *chatv1alpha1.Rocket, resourceInState client.Object, creator model.ResourceCreator) ClusterAction {
-	resource := creator.CreateResource(rocket)
+func getObjectDesiredState(rocket *chatv1alpha1.Rocket, resourceInState client.Object, creator model.ResourceCreator) (ClusterAction, []string, error) {
+	if !creator.IsWanted(rocket) {
+		// resources with the same name created by someone else are left alone
+		if resourceInState == nil || !metav1.IsControlledBy(resourceInState, rocket) {
+			return nil, nil, nil
+		}
+		return GenericDeleteAction{
+			Object: resourceInState,
+			Msg:    fmt.Sprintf("Delete %v", creator.Name()),
+		}, nil, nil
+	}
+	resource, err := model.ApplyOverrides(rocket, creator.CreateResource(rocket))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating desired state of %v: %w", creator.Name(), err)
+	}
 	// resourceInState is nil, doesnt exist
 	if resourceInState == nil {
 		return GenericCreateAction{
 			Object: resource,
 			Msg:    fmt.Sprintf("Create %v", creator.Name()),
-		}
+		}, nil, nil
 	}
-	newResource, needsUpdate := creator.Update(rocket, resourceInState)
-	if needsUpdate {
+	newResource, drifted := creator.Update(resource, resourceInState)
+	if len(drifted) > 0 {
 		return GenericUpdateAction{
 			Object: newResource,
 			Msg:    fmt.Sprintf("Update %v", creator.Name()),
-		}
+		}, drifted, nil
 	}
-	return nil
+	return nil, nil, nil
 }
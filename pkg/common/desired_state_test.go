@@ -0,0 +1,64 @@
+package common
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetObjectDesiredStateOfUnwantedResource(t *testing.T) {
+	rocket := &chatv1alpha1.Rocket{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "1234"},
+	}
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	creator := new(model.RocketIngressCreator)
+
+	owned := creator.CreateResource(rocket)
+	controller := true
+	owned.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: chatv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Rocket",
+		Name:       rocket.Name,
+		UID:        rocket.UID,
+		Controller: &controller,
+	}})
+	foreign := creator.CreateResource(rocket)
+
+	tests := []struct {
+		name       string
+		host       string
+		live       client.Object
+		wantAction ClusterAction
+	}{
+		{name: "wanted and missing", host: "chat.example.com", wantAction: GenericCreateAction{}},
+		{name: "unwanted and missing"},
+		{name: "unwanted and owned", live: owned, wantAction: GenericDeleteAction{}},
+		{name: "unwanted and created by someone else", live: foreign},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket.Spec.IngressSpec.Host = tt.host
+			action, _, err := getObjectDesiredState(rocket, tt.live, creator)
+			if err != nil {
+				t.Fatalf("getObjectDesiredState() error = %v", err)
+			}
+			switch tt.wantAction.(type) {
+			case nil:
+				if action != nil {
+					t.Errorf("getObjectDesiredState() = %v, want no action", action)
+				}
+			case GenericCreateAction:
+				if _, ok := action.(GenericCreateAction); !ok {
+					t.Errorf("getObjectDesiredState() = %T, want a create action", action)
+				}
+			case GenericDeleteAction:
+				if del, ok := action.(GenericDeleteAction); !ok || del.Object != tt.live {
+					t.Errorf("getObjectDesiredState() = %v, want a delete action of the live resource", action)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+)
+
+// Platform contains the optional APIs of the cluster which change the resources created for a rocket.
+// It is discovered once when the manager starts.
+type Platform struct {
+	// Routes is true if the cluster serves OpenShift routes, they replace the ingress of the Ingress exposure
+	Routes bool
+	// Certificates is true if cert-manager is installed and can issue the certificates of ingresses
+	Certificates bool
+	// HTTPRoutes is true if the cluster serves the Gateway API, it is required by the GatewayHTTPRoute exposure
+	HTTPRoutes bool
+	// VolumeSnapshots is true if the CSI snapshotter is installed, it is required by the Snapshot deletion policy
+	VolumeSnapshots bool
+	// RouterServiceAccount is the service account of the OpenShift router which reads the certificates of the routes.
+	// It isn't discovered, sharded or custom routers run with their own service account.
+	RouterServiceAccount types.NamespacedName
+}
+
+// ParseServiceAccount parses a service account in the form <namespace>/<name>
+func ParseServiceAccount(serviceAccount string) (types.NamespacedName, error) {
+	parts := strings.Split(serviceAccount, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("Error parsing service account %q, expected <namespace>/<name>", serviceAccount)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// DiscoverPlatform asks the api server which optional APIs it serves
+func DiscoverPlatform(client discovery.DiscoveryInterface) (Platform, error) {
+	var platform Platform
+	var err error
+	platform.Routes, err = hasKind(client, model.RouteGVK)
+	if err != nil {
+		return platform, fmt.Errorf("Error discovering the OpenShift route API: %w", err)
+	}
+	platform.Certificates, err = hasKind(client, model.CertificateGVK)
+	if err != nil {
+		return platform, fmt.Errorf("Error discovering the cert-manager API: %w", err)
+	}
+	platform.HTTPRoutes, err = hasKind(client, model.HTTPRouteGVK)
+	if err != nil {
+		return platform, fmt.Errorf("Error discovering the Gateway API: %w", err)
+	}
+	platform.VolumeSnapshots, err = hasKind(client, model.VolumeSnapshotGVK)
+	if err != nil {
+		return platform, fmt.Errorf("Error discovering the volume snapshot API: %w", err)
+	}
+	return platform, nil
+}
+
+// hasKind returns true if the api server serves the kind
+func hasKind(client discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == gvk.Kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseServiceAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceAccount string
+		want           types.NamespacedName
+		wantErr        bool
+	}{
+		{name: "default router", serviceAccount: "openshift-ingress/router", want: types.NamespacedName{Namespace: "openshift-ingress", Name: "router"}},
+		{name: "without namespace", serviceAccount: "router", wantErr: true},
+		{name: "empty name", serviceAccount: "openshift-ingress/", wantErr: true},
+		{name: "too many parts", serviceAccount: "a/b/c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServiceAccount(tt.serviceAccount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseServiceAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseServiceAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,9 +8,17 @@ import (
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// progressDeadlineExceeded is the reason of the Progressing condition of a deployment which didn't roll out in time
+const progressDeadlineExceeded = "ProgressDeadlineExceeded"
+
 func (c *ClusterStateReader) isStatefulSetReady(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (bool, error) {
 	// Check statefulset is ready
 	sts := &appsv1.StatefulSet{}
@@ -31,6 +39,32 @@ func (c *ClusterStateReader) isStatefulSetReady(creator model.ResourceCreator, r
 	return numOfReplicasMatch && allReplicasReady && revisionsMatch, nil
 }
 
+// isDatabaseReachable checks if the connectivity check of the external database succeeded.
+// While a check is repeated the result of the previous check is kept from the DatabaseReady condition.
+func (c *ClusterStateReader) isDatabaseReachable(creator *model.MongodbCheckJobCreator, rocket *chatv1alpha1.Rocket) (bool, error) {
+	previous := meta.IsStatusConditionTrue(rocket.Status.Conditions, chatv1alpha1.ConditionDatabaseReady)
+	job := &batchv1.Job{}
+	err := c.client.Get(c.ctx, creator.Selector(rocket), job)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return previous, nil
+		}
+		return false, err
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, nil
+		}
+	}
+	return previous, nil
+}
+
 // isDeploymentReady checks if a deployment is ready.
 // The function checks wether the ReadyReplicas match the wanted Replicas and no replicaFailure condition exists
 func (c *ClusterStateReader) isDeploymentReady(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (bool, error) {
@@ -39,10 +73,14 @@ func (c *ClusterStateReader) isDeploymentReady(creator model.ResourceCreator, ro
 	selector := creator.Selector(rocket)
 	err := c.client.Get(c.ctx, selector, dep)
 	if err != nil {
+		// the deployment might have been created in this reconciliation and isn't visible yet
+		if apiErrors.IsNotFound(err) {
+			return false, nil
+		}
 		return false, err
 	}
-
-	if dep == nil {
+	// a new template isn't ready until the deployment controller rolled it out
+	if dep.Status.ObservedGeneration < dep.Generation || dep.Status.UpdatedReplicas != *dep.Spec.Replicas {
 		return false, nil
 	}
 	// if the desired Replica doesnt match the ReadyReplicas in Status, deployment isn't ready
@@ -61,24 +99,230 @@ func (c *ClusterStateReader) isDeploymentReady(creator model.ResourceCreator, ro
 	return true, nil
 }
 
-// IsResourcesReady checks if the mongodb StatefulSet and the Rocketchat Deployment are ready
-func (c *ClusterStateReader) IsResourcesReady(rocket *chatv1alpha1.Rocket) (bool, error) {
-	var mongodbStatefulSetReady, rocketchatDeploymentReady bool
+// deploymentFailure returns the message of the Progressing condition of the deployment once it exceeded its progress deadline,
+// it is empty while the rollout progresses
+func (c *ClusterStateReader) deploymentFailure(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (string, error) {
+	dep := &appsv1.Deployment{}
+	err := c.client.Get(c.ctx, creator.Selector(rocket), dep)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	// the condition belongs to the template the deployment controller observed last,
+	// a reverted version still carries the failure of the version it replaced until its rollout started
+	if dep.Status.ObservedGeneration < dep.Generation || !runsWebserverVersion(dep, model.RocketWebserverVersion(rocket)) {
+		return "", nil
+	}
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse && condition.Reason == progressDeadlineExceeded {
+			return condition.Message, nil
+		}
+	}
+	return "", nil
+}
+
+// runsWebserverVersion returns true if the webserver container of the deployment runs the version of Rocket.Chat
+func runsWebserverVersion(dep *appsv1.Deployment, version string) bool {
+	for _, container := range dep.Spec.Template.Spec.Containers {
+		if container.Image == model.RocketWebserverImage+":"+version {
+			return true
+		}
+	}
+	return false
+}
+
+// ingressAddress returns the address the ingress controller assigned to the ingress, empty if there is none yet.
+// The ingress is ready once it has an address.
+func (c *ClusterStateReader) ingressAddress(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (string, error) {
+	ingress := &networkingv1.Ingress{}
+	selector := creator.Selector(rocket)
+	err := c.client.Get(c.ctx, selector, ingress)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname, nil
+		}
+		if lb.IP != "" {
+			return lb.IP, nil
+		}
+	}
+	return "", nil
+}
+
+// serviceAddress returns the address the cloud provider assigned to the load balancer of the service, empty if there is none yet
+func (c *ClusterStateReader) serviceAddress(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (string, error) {
+	service := &corev1.Service{}
+	err := c.client.Get(c.ctx, creator.Selector(rocket), service)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, lb := range service.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname, nil
+		}
+		if lb.IP != "" {
+			return lb.IP, nil
+		}
+	}
+	return "", nil
+}
+
+// isHTTPRouteAccepted checks if a gateway the HTTPRoute references accepted the route
+func (c *ClusterStateReader) isHTTPRouteAccepted(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (bool, error) {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(model.HTTPRouteGVK)
+	err := c.client.Get(c.ctx, creator.Selector(rocket), route)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		for _, cond := range conditions {
+			condition, ok := cond.(map[string]interface{})
+			if ok && condition["type"] == "Accepted" && condition["status"] == "True" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// routeAdmission returns true once a router admitted the route and the hostname of the router
+func (c *ClusterStateReader) routeAdmission(creator model.ResourceCreator, rocket *chatv1alpha1.Rocket) (bool, string, error) {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(model.RouteGVK)
+	err := c.client.Get(c.ctx, creator.Selector(rocket), route)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	ingresses, _, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+	for _, i := range ingresses {
+		ingress, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(ingress, "conditions")
+		for _, cond := range conditions {
+			condition, ok := cond.(map[string]interface{})
+			if ok && condition["type"] == "Admitted" && condition["status"] == "True" {
+				hostname, _, _ := unstructured.NestedString(ingress, "routerCanonicalHostname")
+				return true, hostname, nil
+			}
+		}
+	}
+	return false, "", nil
+}
+
+// ResourcesReadiness contains the readiness of the components of a rocket instance
+type ResourcesReadiness struct {
+	Database  bool
+	Webserver bool
+	Ingress   bool
+	// Exposed is true if the rocket has a resource exposing Rocket.Chat outside of the cluster, Ingress is only meaningful then
+	Exposed bool
+	// WebserverFailure is the reason the rollout of the webserver failed, empty while it progresses
+	WebserverFailure string
+	// IngressAddress is the hostname or IP the ingress controller assigned to the ingress,
+	// the cloud provider assigned to the load balancer or the hostname of the router admitting the route
+	IngressAddress string
+}
+
+// Ready returns true if the database and the webserver are ready.
+// The ingress isn't taken into account, since not every cluster assigns addresses to ingresses.
+func (r ResourcesReadiness) Ready() bool {
+	return r.Database && r.Webserver
+}
+
+// IsResourcesReady checks if the mongodb StatefulSet, the Rocketchat Deployment and the Ingress are ready.
+// An external database is ready once its connectivity check succeeded.
+func (c *ClusterStateReader) IsResourcesReady(rocket *chatv1alpha1.Rocket) (ResourcesReadiness, error) {
+	var readiness ResourcesReadiness
 	var err error
-	for creator := range c.state {
+	for _, creator := range c.creators {
+		if !creator.IsWanted(rocket) {
+			continue
+		}
 		if val, ok := creator.(*model.MongodbStatefulSetCreator); ok {
-			mongodbStatefulSetReady, err = c.isStatefulSetReady(val, rocket)
+			readiness.Database, err = c.isStatefulSetReady(val, rocket)
+			if err != nil {
+				return readiness, fmt.Errorf("Error determining if statefulSet is ready: %w", err)
+			}
+		}
+		if val, ok := creator.(*model.MongodbCheckJobCreator); ok {
+			readiness.Database, err = c.isDatabaseReachable(val, rocket)
 			if err != nil {
-				return false, fmt.Errorf("Error determining if statefulSet is ready: %w", err)
+				return readiness, fmt.Errorf("Error determining if the connectivity check succeeded: %w", err)
 			}
 		}
 		if val, ok := creator.(*model.RocketDeploymentCreator); ok {
-			rocketchatDeploymentReady, err = c.isDeploymentReady(val, rocket)
+			readiness.Webserver, err = c.isDeploymentReady(val, rocket)
+			if err != nil {
+				return readiness, fmt.Errorf("Error determining if deployment is ready: %w", err)
+			}
+			readiness.WebserverFailure, err = c.deploymentFailure(val, rocket)
+			if err != nil {
+				return readiness, fmt.Errorf("Error determining if deployment failed: %w", err)
+			}
+		}
+		if val, ok := creator.(*model.RocketIngressCreator); ok {
+			readiness.Exposed = true
+			readiness.IngressAddress, err = c.ingressAddress(val, rocket)
+			if err != nil {
+				return readiness, fmt.Errorf("Error determining if ingress is ready: %w", err)
+			}
+			readiness.Ingress = readiness.IngressAddress != ""
+		}
+		if val, ok := creator.(*model.RouteCreator); ok {
+			readiness.Exposed = true
+			readiness.Ingress, readiness.IngressAddress, err = c.routeAdmission(val, rocket)
 			if err != nil {
-				return false, fmt.Errorf("Error determining if deployment is ready: %w", err)
+				return readiness, fmt.Errorf("Error determining if route is admitted: %w", err)
+			}
+		}
+		if val, ok := creator.(*model.RocketHTTPRouteCreator); ok {
+			readiness.Exposed = true
+			readiness.Ingress, err = c.isHTTPRouteAccepted(val, rocket)
+			if err != nil {
+				return readiness, fmt.Errorf("Error determining if HTTPRoute is accepted: %w", err)
+			}
+		}
+		if val, ok := creator.(*model.RocketServiceCreator); ok {
+			switch rocket.ExposureType() {
+			case chatv1alpha1.ExposureLoadBalancer:
+				readiness.Exposed = true
+				readiness.IngressAddress, err = c.serviceAddress(val, rocket)
+				if err != nil {
+					return readiness, fmt.Errorf("Error determining if load balancer is ready: %w", err)
+				}
+				readiness.Ingress = readiness.IngressAddress != ""
+			case chatv1alpha1.ExposureNodePort:
+				// the node ports are allocated with the service
+				readiness.Exposed = true
+				readiness.Ingress = true
 			}
 		}
 	}
 
-	return mongodbStatefulSetReady && rocketchatDeploymentReady, nil
+	return readiness, nil
 }
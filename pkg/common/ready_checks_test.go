@@ -0,0 +1,115 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeploymentFailure(t *testing.T) {
+	rocket := &chatv1alpha1.Rocket{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	rocket.Spec.AdminSpec = &chatv1alpha1.RocketAdminSpec{Username: "admin", Email: "admin@example.com"}
+	model.SetRocketDefaults(rocket)
+	rocket.Spec.Version = "4.0.0"
+	rocket.Status.WebserverVersion = "4.0.0"
+	creator := new(model.RocketDeploymentCreator)
+
+	failed := func(mutate func(dep *appsv1.Deployment)) *appsv1.Deployment {
+		dep := creator.CreateResource(rocket).(*appsv1.Deployment)
+		dep.Generation = 2
+		dep.Status.ObservedGeneration = 2
+		dep.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  progressDeadlineExceeded,
+			Message: "ReplicaSet has timed out progressing.",
+		}}
+		mutate(dep)
+		return dep
+	}
+	tests := []struct {
+		name string
+		dep  *appsv1.Deployment
+		want string
+	}{
+		{name: "failed version", dep: failed(func(dep *appsv1.Deployment) {}), want: "ReplicaSet has timed out progressing."},
+		{
+			name: "reverted version not observed yet",
+			dep:  failed(func(dep *appsv1.Deployment) { dep.Generation = 3 }),
+		},
+		{
+			name: "deployment still running the failed version",
+			dep: failed(func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Image = model.RocketWebserverImage + ":4.1.0"
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(tt.dep).Build()
+			reader := &ClusterStateReader{client: client, instance: rocket, ctx: context.TODO()}
+			got, err := reader.deploymentFailure(creator, rocket)
+			if err != nil {
+				t.Fatalf("deploymentFailure() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("deploymentFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDatabaseReachable(t *testing.T) {
+	rocket := &chatv1alpha1.Rocket{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	rocket.Spec.Database.External = &chatv1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "mongodb"}}
+	creator := new(model.MongodbCheckJobCreator)
+
+	finished := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		job := creator.CreateResource(rocket).(*batchv1.Job)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+		return job
+	}
+	tests := []struct {
+		name      string
+		job       *batchv1.Job
+		reachable bool
+		want      bool
+	}{
+		{name: "first check running", job: creator.CreateResource(rocket).(*batchv1.Job)},
+		{name: "check succeeded", job: finished(batchv1.JobComplete), want: true},
+		{name: "check failed", job: finished(batchv1.JobFailed), reachable: true},
+		{name: "repeated check running", job: creator.CreateResource(rocket).(*batchv1.Job), reachable: true, want: true},
+		{name: "repeated check not created yet", reachable: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme)
+			if tt.job != nil {
+				builder = builder.WithObjects(tt.job)
+			}
+			rocket := rocket.DeepCopy()
+			status := metav1.ConditionFalse
+			if tt.reachable {
+				status = metav1.ConditionTrue
+			}
+			meta.SetStatusCondition(&rocket.Status.Conditions, metav1.Condition{Type: chatv1alpha1.ConditionDatabaseReady, Status: status, Reason: "Test"})
+			reader := &ClusterStateReader{client: builder.Build(), instance: rocket, ctx: context.TODO()}
+			got, err := reader.isDatabaseReachable(creator, rocket)
+			if err != nil {
+				t.Fatalf("isDatabaseReachable() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isDatabaseReachable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
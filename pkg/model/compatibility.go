@@ -0,0 +1,157 @@
+package model
+
+import (
+	// the catalog is embedded into the binary
+	_ "embed"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed compatibility.yaml
+var embeddedCompatibilityCatalog []byte
+
+var defaultCompatibilityCatalog = mustParseCompatibilityCatalog(embeddedCompatibilityCatalog)
+
+// CompatibilityCatalog lists the mongodb release series supported by Rocket.Chat releases
+type CompatibilityCatalog struct {
+	Webserver []WebserverCompatibility `json:"webserver"`
+}
+
+// WebserverCompatibility contains the mongodb release series supported by Rocket.Chat releases
+type WebserverCompatibility struct {
+	// Version is a Rocket.Chat release or a prefix of releases, e.g. 4 for all 4.x releases
+	Version string `json:"version"`
+	// Databases are the supported mongodb release series, e.g. 4.4
+	Databases []string `json:"databases"`
+}
+
+// DefaultCompatibilityCatalog returns the catalog embedded into the operator
+func DefaultCompatibilityCatalog() *CompatibilityCatalog {
+	return defaultCompatibilityCatalog
+}
+
+// LoadCompatibilityCatalog reads the catalog file at path and merges it into the embedded catalog.
+// Entries of the file replace embedded entries with the same version. The embedded catalog is returned for an empty path.
+func LoadCompatibilityCatalog(path string) (*CompatibilityCatalog, error) {
+	if path == "" {
+		return DefaultCompatibilityCatalog(), nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading compatibility catalog: %w", err)
+	}
+	overrides, err := ParseCompatibilityCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &CompatibilityCatalog{}
+	for _, entry := range defaultCompatibilityCatalog.Webserver {
+		if _, overridden := overrides.entry(entry.Version); !overridden {
+			catalog.Webserver = append(catalog.Webserver, entry)
+		}
+	}
+	catalog.Webserver = append(catalog.Webserver, overrides.Webserver...)
+	return catalog, nil
+}
+
+// ParseCompatibilityCatalog parses a catalog in yaml format
+func ParseCompatibilityCatalog(data []byte) (*CompatibilityCatalog, error) {
+	catalog := &CompatibilityCatalog{}
+	if err := yaml.UnmarshalStrict(data, catalog); err != nil {
+		return nil, fmt.Errorf("Error parsing compatibility catalog: %w", err)
+	}
+	for _, entry := range catalog.Webserver {
+		if entry.Version == "" {
+			return nil, fmt.Errorf("Error parsing compatibility catalog: entry without version")
+		}
+		for _, series := range entry.Databases {
+			if _, err := mongodbSeriesIndex(series); err != nil {
+				return nil, fmt.Errorf("Error parsing compatibility catalog entry %v: %w", entry.Version, err)
+			}
+		}
+	}
+	return catalog, nil
+}
+
+func mustParseCompatibilityCatalog(data []byte) *CompatibilityCatalog {
+	catalog, err := ParseCompatibilityCatalog(data)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+func (c *CompatibilityCatalog) entry(version string) (WebserverCompatibility, bool) {
+	for _, entry := range c.Webserver {
+		if entry.Version == version {
+			return entry, true
+		}
+	}
+	return WebserverCompatibility{}, false
+}
+
+// SupportedDatabases returns the mongodb release series supported by the Rocket.Chat release.
+// It returns false if the catalog doesn't contain the release.
+func (c *CompatibilityCatalog) SupportedDatabases(webserverVersion string) ([]string, bool) {
+	var match *WebserverCompatibility
+	for i, entry := range c.Webserver {
+		if webserverVersion != entry.Version && !strings.HasPrefix(webserverVersion, entry.Version+".") {
+			continue
+		}
+		if match == nil || len(entry.Version) > len(match.Version) {
+			match = &c.Webserver[i]
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+	return match.Databases, true
+}
+
+// CheckCompatibility returns an error explaining the required database change
+// if the Rocket.Chat release doesn't support the mongodb version.
+func (c *CompatibilityCatalog) CheckCompatibility(webserverVersion, databaseVersion string) error {
+	supported, ok := c.SupportedDatabases(webserverVersion)
+	if !ok {
+		return nil
+	}
+	series, err := MongodbReleaseSeries(databaseVersion)
+	if err != nil {
+		return err
+	}
+	for _, s := range supported {
+		if s == series {
+			return nil
+		}
+	}
+	if len(supported) == 0 {
+		return fmt.Errorf("Rocket.Chat %v doesn't support any mongodb release", webserverVersion)
+	}
+
+	required := fmt.Sprintf("Rocket.Chat %v requires mongodb %v, but the database runs %v", webserverVersion, strings.Join(supported, ", "), databaseVersion)
+	current, err := mongodbSeriesIndex(databaseVersion)
+	if err != nil {
+		return fmt.Errorf("%v: %w", required, err)
+	}
+	for _, s := range supported {
+		// supported series are upgraded to one release series at a time
+		if index, _ := mongodbSeriesIndex(s); index > current {
+			return fmt.Errorf("%v, upgrade the database to %v first", required, mongodbReleaseSeries[current+1])
+		}
+	}
+	return fmt.Errorf("%v, mongodb can't be downgraded to a supported release", required)
+}
+
+// RocketWebserverVersion returns the version of Rocket.Chat deployed for the rocket.
+// The controller holds back spec.version in the status until the database supports it.
+func RocketWebserverVersion(rocket *chatv1alpha1.Rocket) string {
+	if rocket.Status.WebserverVersion != "" {
+		return rocket.Status.WebserverVersion
+	}
+	return rocket.Spec.Version
+}
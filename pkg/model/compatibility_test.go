@@ -0,0 +1,74 @@
+package model
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name            string
+		webserver       string
+		database        string
+		wantErrContains string
+	}{
+		{name: "default versions", webserver: RocketWebserverDefaultVersion, database: MongodbDefaultVersion},
+		{name: "more specific entry", webserver: "3.18.2", database: "5.0.3"},
+		{name: "less specific entry", webserver: "3.17.0", database: "5.0.3", wantErrContains: "mongodb can't be downgraded"},
+		{name: "database too old", webserver: "6.0.0", database: "4.2.17", wantErrContains: "upgrade the database to 4.4 first"},
+		{name: "database too new", webserver: "5.4.0", database: "6.0.2", wantErrContains: "mongodb can't be downgraded"},
+		{name: "release without entry", webserver: "7.0.0", database: "4.2.17"},
+		{name: "prefix of the release", webserver: "60.0.0", database: "4.2.17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultCompatibilityCatalog().CheckCompatibility(tt.webserver, tt.database)
+			if tt.wantErrContains == "" {
+				if err != nil {
+					t.Errorf("CheckCompatibility() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrContains) {
+				t.Errorf("CheckCompatibility() error = %v, want it to contain %q", err, tt.wantErrContains)
+			}
+		})
+	}
+}
+
+func TestLoadCompatibilityCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.yaml")
+	overrides := `webserver:
+- version: "6"
+  databases: ["5.0", "6.0"]
+- version: "7"
+  databases: ["6.0", "7.0"]
+`
+	if err := ioutil.WriteFile(path, []byte(overrides), 0600); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := LoadCompatibilityCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCompatibilityCatalog() error = %v", err)
+	}
+
+	for version, want := range map[string][]string{
+		"3.18.2": {"3.6", "4.0", "4.2", "4.4", "5.0"},
+		"6.0.0":  {"5.0", "6.0"},
+		"7.1.0":  {"6.0", "7.0"},
+	} {
+		if got, _ := catalog.SupportedDatabases(version); !reflect.DeepEqual(got, want) {
+			t.Errorf("SupportedDatabases(%v) = %v, want %v", version, got, want)
+		}
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`webserver: [{version: "6", databases: ["9.9"]}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCompatibilityCatalog(path); err == nil {
+		t.Errorf("LoadCompatibilityCatalog() accepted an unknown mongodb release series")
+	}
+}
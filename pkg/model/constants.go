@@ -3,8 +3,11 @@ package model
 // Constants for a rocket chat installation
 const (
 	MongodbComponentName          = "mongodb"
+	MongodbImage                  = "docker.io/bitnami/mongodb"
 	MongodbTargetPort             = "mongodb"
 	MongodbDefaultVersion         = "4.4.10"
+	MongodbDefaultReplicas        = 1
+	MongodbDefaultStorageSize     = "8Gi"
 	MongodbScriptPath             = "/scripts/setup.sh"
 	MongodbStatefulSetSuffix      = "-mongodb"
 	MongodbServiceSuffix          = "-mongodb-service"
@@ -12,12 +15,50 @@ const (
 	MongodbScriptsConfigmapSuffix = "-mongodb-scripts"
 	MongodbVolumeSuffix           = "-datadir"
 	MongodbAuthSecretSuffix       = "-mongodb-auth"
+	MongodbVolumeSnapshotSuffix   = "-final-snapshot"
+	MongodbBackupComponentName    = "backup"
+	MongodbBackupJobSuffix        = "-backup"
+	MongodbBackupArchiveSuffix    = ".archive.gz"
+	MongodbBackupMountPath        = "/backup"
+	MongodbRestoreJobSuffix       = "-restore"
+	MongodbUpgradeComponentName   = "database-upgrade"
+	MongodbUpgradeJobSuffix       = "-mongodb-fcv"
+	MongodbCheckComponentName     = "database-check"
+	MongodbCheckJobSuffix         = "-mongodb-check"
+	MongodbScheduledBackupPrefix  = "-scheduled-"
+	MinioClientImage              = "docker.io/minio/mc:RELEASE.2021-11-16T20-37-36Z"
+
+	// RocketFinalizer enforces the deletion policy of the database before a Rocket is removed
+	RocketFinalizer = "chat.accso.de/finalizer"
+	// SettingsHashAnnotation is the hash of the settings sources on the pod template of the webserver
+	SettingsHashAnnotation = "chat.accso.de/settings-hash"
+	// RetainedFromAnnotation marks resources kept after the deletion of the Rocket named in its value
+	RetainedFromAnnotation = "chat.accso.de/retained-from"
 
 	RocketAdminSecretSuffix         = "-admin"
 	RocketWebserverComponentName    = "webserver"
+	RocketWebserverImage            = "rocketchat/rocket.chat"
 	RocketWebserverDefaultVersion   = "3.18.2"
+	RocketWebserverDefaultReplicas  = 1
 	RocketWebserverDeploymentSuffix = "-rocketchat"
 	RocketWebserverServiceSuffix    = "-rocketchat-service"
+	RocketUpgradeBackupInfix        = "-upgrade-"
+	RocketRollbackRestoreInfix      = "-rollback-"
+	RocketUploadsPath               = "/app/uploads"
+	RocketSettingEnvPrefix          = "OVERWRITE_SETTING_"
+	RocketTLSSecretSuffix           = "-tls"
+	RocketServicePort               = 80
+	RouteCertificateReaderSuffix    = "-route-certificate-reader"
+	RouteServingCertSuffix          = "-serving-cert"
+	RouteTLSProxyImage              = "docker.io/ghostunnel/ghostunnel:v1.7.1"
+	RouteTLSProxyPort               = 8443
+	RouteTLSProxyServicePort        = 443
+	RouteTLSProxyCertPath           = "/etc/tls/serving"
+	// ServingCertSecretAnnotation lets the OpenShift service CA issue a certificate for the service into the named secret
+	ServingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// RouterServiceAccount of the default OpenShift router reads the certificates of routes
+	RouterServiceAccount = "router"
+	RouterNamespace      = "openshift-ingress"
 )
 
 var (
@@ -25,7 +66,12 @@ var (
 	RocketWebserverGroup    = int64(999)
 	MongodbScriptMode       = int32(0755)
 	MongodbUser             = int64(1001)
-	MongodbReadinessCommand = `# Run the proper check depending on the version
+	MongodbReadinessCommand = `# mongodb 6.0 replaced the legacy mongo shell with mongosh
+if command -v mongosh > /dev/null; then
+    mongosh $TLS_OPTIONS --quiet --eval 'db.hello().isWritablePrimary || db.hello().secondary' | grep -q 'true'
+    exit
+fi
+# Run the proper check depending on the version
 [[ $(mongo --version | grep "MongoDB shell") =~ ([0-9]+\.[0-9]+\.[0-9]+) ]] && VERSION=${BASH_REMATCH[1]}
 . /opt/bitnami/scripts/libversion.sh
 VERSION_MAJOR="$(get_sematic_version "$VERSION" 1)"
@@ -35,6 +81,11 @@ if [[ "$VERSION_MAJOR" -ge 4 ]] && [[ "$VERSION_MINOR" -ge 4 ]] && [[ "$VERSION_
     mongo --disableImplicitSessions $TLS_OPTIONS --eval 'db.hello().isWritablePrimary || db.hello().secondary' | grep -q 'true'
 else
     mongo --disableImplicitSessions $TLS_OPTIONS --eval 'db.isMaster().ismaster || db.isMaster().secondary' | grep -q 'true'
+fi`
+	MongodbLivenessCommand = `if command -v mongosh > /dev/null; then
+    mongosh --quiet --eval "db.adminCommand('ping')"
+else
+    mongo --disableImplicitSessions --eval "db.adminCommand('ping')"
 fi`
 	boolTrue = true
 )
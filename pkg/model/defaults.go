@@ -0,0 +1,64 @@
+package model
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SetRocketDefaults fills all unset fields of the rocket with their default values.
+// It is used by the defaulting webhook and by the controller for objects admitted without the webhook.
+func SetRocketDefaults(rocket *chatv1alpha1.Rocket) {
+	if !util.HasDefaultLabels(rocket) {
+		rocket.Labels = util.MergeLabels(rocket.Labels, util.DefaultLabels(rocket.Name))
+	}
+
+	spec := &rocket.Spec
+	if spec.Version == "" {
+		spec.Version = RocketWebserverDefaultVersion
+	}
+	if spec.Replicas == nil {
+		spec.Replicas = util.CreatePointerInt32(RocketWebserverDefaultReplicas)
+	}
+
+	database := &spec.Database
+	if database.Version == "" {
+		database.Version = MongodbDefaultVersion
+	}
+	if database.Replicas == 0 {
+		database.Replicas = MongodbDefaultReplicas
+	}
+	if database.DeletionPolicy == "" {
+		database.DeletionPolicy = chatv1alpha1.DeletionPolicyRetain
+	}
+	setStorageDefaults(rocket)
+}
+
+// setStorageDefaults fills the claim template of the database, no volumes are claimed for an external database
+func setStorageDefaults(rocket *chatv1alpha1.Rocket) {
+	if rocket.HasExternalDatabase() {
+		return
+	}
+	if rocket.Spec.Database.StorageSpec == nil {
+		rocket.Spec.Database.StorageSpec = &chatv1alpha1.EmbeddedPersistentVolumeClaim{}
+	}
+	claimTemplate := rocket.Spec.Database.StorageSpec
+
+	if claimTemplate.Name == "" {
+		claimTemplate.Name = rocket.Name + MongodbVolumeSuffix
+	}
+	if claimTemplate.Spec.AccessModes == nil {
+		claimTemplate.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+	if claimTemplate.Spec.VolumeMode == nil {
+		defaultVolumeMode := corev1.PersistentVolumeFilesystem
+		claimTemplate.Spec.VolumeMode = &defaultVolumeMode
+	}
+	if _, ok := claimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		if claimTemplate.Spec.Resources.Requests == nil {
+			claimTemplate.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claimTemplate.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(MongodbDefaultStorageSize)
+	}
+}
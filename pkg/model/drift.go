@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DriftedFields compares the desired resource semantically with the live resource from the cluster
+// and returns the paths of all fields that are set in the desired resource but differ in the live resource.
+// Fields that are unset in the desired resource are defaulted by the api server and are ignored,
+// lists have to match in length and are compared element by element.
+// From the metadata only labels and annotations are compared, status is never compared.
+// Fields starting with one of the ignored paths (e.g. spec.selector) aren't compared at all.
+func DriftedFields(desired, live client.Object, ignored ...string) []string {
+	desiredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return []string{fmt.Sprintf("<unconvertible: %v>", err)}
+	}
+	liveMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return []string{fmt.Sprintf("<unconvertible: %v>", err)}
+	}
+
+	d := &driftDetector{ignored: ignored}
+	for key, value := range desiredMap {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			desiredMeta, _ := value.(map[string]interface{})
+			liveMeta, _ := liveMap[key].(map[string]interface{})
+			for _, metaKey := range []string{"labels", "annotations"} {
+				d.compare("metadata."+metaKey, desiredMeta[metaKey], liveMeta[metaKey])
+			}
+		default:
+			d.compare(key, value, liveMap[key])
+		}
+	}
+	sort.Strings(d.drifted)
+	return d.drifted
+}
+
+type driftDetector struct {
+	ignored []string
+	drifted []string
+}
+
+func (d *driftDetector) isIgnored(path string) bool {
+	for _, prefix := range d.ignored {
+		if path == prefix || strings.HasPrefix(path, prefix+".") || strings.HasPrefix(path, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *driftDetector) compare(path string, desired, live interface{}) {
+	if d.isIgnored(path) || isZero(desired) {
+		return
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			d.drifted = append(d.drifted, path)
+			return
+		}
+		for key, value := range desiredValue {
+			d.compare(path+"."+key, value, liveValue[key])
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(desiredValue) {
+			d.drifted = append(d.drifted, path)
+			return
+		}
+		for i := range desiredValue {
+			d.compare(fmt.Sprintf("%v[%d]", path, i), desiredValue[i], liveValue[i])
+		}
+	default:
+		if !equality.Semantic.DeepEqual(desired, live) {
+			d.drifted = append(d.drifted, path)
+		}
+	}
+}
+
+// isZero returns true for values that are unset in the desired resource
+func isZero(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testRocket() *v1alpha1.Rocket {
+	rocket := &v1alpha1.Rocket{
+		ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: v1alpha1.RocketSpec{
+			AdminSpec: &v1alpha1.RocketAdminSpec{Username: "admin", Email: "admin@example.com"},
+		},
+	}
+	SetRocketDefaults(rocket)
+	return rocket
+}
+
+// liveDeployment simulates a deployment read from the cluster, with fields defaulted by the api server
+func liveDeployment(rocket *v1alpha1.Rocket) *appsv1.Deployment {
+	// deep copy, the created resource shares the label map with the rocket
+	dep := new(RocketDeploymentCreator).CreateResource(rocket).DeepCopyObject().(*appsv1.Deployment)
+	dep.ResourceVersion = "42"
+	dep.Labels["added-by"] = "someone-else"
+	dep.Spec.RevisionHistoryLimit = new(int32)
+	container := &dep.Spec.Template.Spec.Containers[0]
+	container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	container.ImagePullPolicy = corev1.PullIfNotPresent
+	container.Ports[0].Protocol = corev1.ProtocolTCP
+	container.LivenessProbe.TimeoutSeconds = 1
+	dep.Status.Replicas = 1
+	return dep
+}
+
+func TestDriftedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(dep *appsv1.Deployment)
+		want   []string
+	}{
+		{
+			name:   "server defaulted fields",
+			mutate: func(dep *appsv1.Deployment) {},
+		},
+		{
+			name: "changed image",
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Image = "rocket.chat:latest"
+			},
+			want: []string{"spec.template.spec.containers[0].image"},
+		},
+		{
+			name: "added env var",
+			mutate: func(dep *appsv1.Deployment) {
+				container := &dep.Spec.Template.Spec.Containers[0]
+				container.Env = append(container.Env, corev1.EnvVar{Name: "DEBUG", Value: "true"})
+			},
+			want: []string{"spec.template.spec.containers[0].env"},
+		},
+		{
+			name: "changed probe and replicas",
+			mutate: func(dep *appsv1.Deployment) {
+				replicas := int32(5)
+				dep.Spec.Replicas = &replicas
+				dep.Spec.Template.Spec.Containers[0].ReadinessProbe.InitialDelaySeconds = 1
+			},
+			want: []string{
+				"spec.replicas",
+				"spec.template.spec.containers[0].readinessProbe.initialDelaySeconds",
+			},
+		},
+		{
+			name: "removed label",
+			mutate: func(dep *appsv1.Deployment) {
+				delete(dep.Labels, "rocketchat")
+			},
+			want: []string{"metadata.labels.rocketchat"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			desired := new(RocketDeploymentCreator).CreateResource(rocket)
+			live := liveDeployment(rocket)
+			tt.mutate(live)
+			if got := DriftedFields(desired, live); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DriftedFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriftedFieldsIgnored(t *testing.T) {
+	rocket := testRocket()
+	desired := new(RocketDeploymentCreator).CreateResource(rocket)
+	live := liveDeployment(rocket)
+	live.Spec.Selector.MatchLabels = map[string]string{"app": "other"}
+	if got := DriftedFields(desired, live, "spec.selector"); len(got) != 0 {
+		t.Errorf("DriftedFields() = %v, want no drift", got)
+	}
+}
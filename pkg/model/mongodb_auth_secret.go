@@ -44,7 +44,33 @@ func (c *MongodbAuthSecretCreator) Selector(rocket *chatv1alpha1.Rocket) client.
 		Namespace: rocket.Namespace,
 	}
 }
-func (c *MongodbAuthSecretCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	// never update auth secret!
-	return cur, false
+func (c *MongodbAuthSecretCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// never update the generated credentials of the auth secret!
+	drifted := DriftedFields(desired, cur, "data")
+	if len(drifted) == 0 {
+		return cur, nil
+	}
+	secret := desired.(*corev1.Secret)
+	secret.Data = cur.(*corev1.Secret).Data
+	return secret, drifted
+}
+
+// DependsOn returns the creators of the resources the MongodbAuthSecretCreator depends on
+func (c *MongodbAuthSecretCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, the auth secret is only added for a database deployed by the operator.
+// An external database has its own connection secret.
+func (c *MongodbAuthSecretCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
+
+// DatabaseSecretReference references the secret with the connection strings of the database in the keys uri and oplog-uri.
+// It is the auth secret of the operator unless the rocket uses an external database.
+func DatabaseSecretReference(rocket *chatv1alpha1.Rocket) corev1.LocalObjectReference {
+	if rocket.HasExternalDatabase() {
+		return rocket.Spec.Database.External.SecretRef
+	}
+	return corev1.LocalObjectReference{Name: new(MongodbAuthSecretCreator).Selector(rocket).Name}
 }
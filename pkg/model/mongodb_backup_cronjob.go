@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type MongodbBackupCronJobCreator struct{}
+
+// Name returns the ressource action of the MongodbBackupCronJobCreator
+func (c *MongodbBackupCronJobCreator) Name() string {
+	return "Mongodb Backup CronJob"
+}
+
+func (c *MongodbBackupCronJobCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	schedule := rocket.Spec.Backup
+	if schedule == nil {
+		schedule = &chatv1alpha1.RocketBackupSchedule{}
+	}
+	historyLimit := int32(3)
+	suspend := schedule.Suspend
+	// archives of scheduled backups are named after the time the job started
+	archive := fmt.Sprintf(`"%v$(date -u +%%Y%%m%%d%%H%%M%%S)%v"`, rocket.Name+MongodbScheduledBackupPrefix, MongodbBackupArchiveSuffix)
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rocket.Name + MongodbBackupJobSuffix,
+			Namespace: rocket.Namespace,
+			Labels:    rocket.Labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   schedule.Schedule,
+			Suspend:                    &suspend,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: backupLabels(rocket),
+				},
+				Spec: mongodumpJobSpec(rocket, schedule.Target, archive, &schedule.Retention),
+			},
+		},
+	}
+}
+
+func (c *MongodbBackupCronJobCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name + MongodbBackupJobSuffix,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *MongodbBackupCronJobCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	drifted := DriftedFields(desired, cur)
+	// unsuspending sets suspend to false, which isn't compared as it is a zero value
+	desiredSuspend, curSuspend := desired.(*batchv1.CronJob).Spec.Suspend, cur.(*batchv1.CronJob).Spec.Suspend
+	if curSuspend != nil && *curSuspend != *desiredSuspend {
+		drifted = append(drifted, "spec.suspend")
+	}
+	return desired, drifted
+}
+
+// DependsOn returns the creators of the resources the MongodbBackupCronJobCreator depends on
+func (c *MongodbBackupCronJobCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{
+		new(MongodbAuthSecretCreator),
+		&MongodbServiceCreator{Headless: false},
+	}
+}
+
+// IsWanted returns true if backups are scheduled
+func (c *MongodbBackupCronJobCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return rocket.Spec.Backup != nil
+}
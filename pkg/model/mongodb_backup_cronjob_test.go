@@ -0,0 +1,84 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMongodbBackupCronJob(t *testing.T) {
+	retention := v1alpha1.BackupRetention{Count: 7, MaxAge: &v1.Duration{Duration: 30 * 24 * time.Hour}}
+	tests := []struct {
+		name           string
+		target         v1alpha1.BackupTarget
+		initContainers int
+		// scripts of the containers which have to contain the strings
+		want map[string][]string
+	}{
+		{
+			name:   "claim",
+			target: v1alpha1.BackupTarget{ClaimName: "backups"},
+			want: map[string][]string{
+				MongodbBackupComponentName: {
+					"mongodump", "-mmin +43200 -delete", "head -n -7", "'test-scheduled-*.archive.gz'", "/dev/termination-log",
+				},
+			},
+		},
+		{
+			name: "bucket",
+			target: v1alpha1.BackupTarget{S3: &v1alpha1.S3Target{
+				Endpoint:          "http://minio.minio:9000",
+				Bucket:            "backups",
+				Prefix:            "chat",
+				CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+			}},
+			initContainers: 2,
+			want: map[string][]string{
+				MongodbBackupComponentName: {"mongodump", `ARCHIVE="backups/chat"/"$ARCHIVE"`},
+				"upload":                   {`mc cp /backup/*.archive.gz "target/backups/chat/"`, "--older-than 43200m", "head -n -7"},
+				"result":                   {"/dev/termination-log"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *", Target: tt.target, Retention: retention}
+			cronJob := new(MongodbBackupCronJobCreator).CreateResource(rocket).(*batchv1.CronJob)
+
+			if cronJob.Spec.Schedule != "0 2 * * *" {
+				t.Errorf("CreateResource() schedule = %v", cronJob.Spec.Schedule)
+			}
+			pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+			if len(pod.InitContainers) != tt.initContainers {
+				t.Errorf("CreateResource() has %v init containers, want %v", len(pod.InitContainers), tt.initContainers)
+			}
+			for _, container := range append(pod.InitContainers, pod.Containers...) {
+				for _, want := range tt.want[container.Name] {
+					if script := container.Command[2]; !strings.Contains(script, want) {
+						t.Errorf("script of container %v doesn't contain %v:\n%v", container.Name, want, script)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMongodbBackupCronJobUnsuspend(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *", Target: v1alpha1.BackupTarget{ClaimName: "backups"}}
+	creator := new(MongodbBackupCronJobCreator)
+	cur := creator.CreateResource(rocket).DeepCopyObject().(*batchv1.CronJob)
+	suspended := true
+	cur.Spec.Suspend = &suspended
+
+	_, drifted := creator.Update(creator.CreateResource(rocket), cur)
+	if len(drifted) != 1 || drifted[0] != "spec.suspend" {
+		t.Errorf("Update() drifted = %v, want [spec.suspend]", drifted)
+	}
+}
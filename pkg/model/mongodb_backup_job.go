@@ -0,0 +1,264 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupResult is written as termination message by the backup container
+type BackupResult struct {
+	// Archive is the path of the archive on the claim or its key in the bucket
+	Archive string `json:"archive"`
+	// Size of the archive in bytes
+	Size int64 `json:"size"`
+}
+
+// ParseBackupResult parses the termination message of the backup container
+func ParseBackupResult(message string) (BackupResult, error) {
+	var result BackupResult
+	if err := json.Unmarshal([]byte(message), &result); err != nil {
+		return result, fmt.Errorf("Error parsing backup result %q: %w", message, err)
+	}
+	return result, nil
+}
+
+// BackupArchiveName returns the name of the archive of the backup on its target
+func BackupArchiveName(backup *chatv1alpha1.RocketBackup) string {
+	return backup.Name + MongodbBackupArchiveSuffix
+}
+
+// MongodumpJob returns the job dumping the database of the rocket into a compressed archive on the target of the backup
+func MongodumpJob(backup *chatv1alpha1.RocketBackup, rocket *chatv1alpha1.Rocket) *batchv1.Job {
+	labels := backupLabels(rocket)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.Name + MongodbBackupJobSuffix,
+			Namespace: backup.Namespace,
+			Labels:    labels,
+		},
+		Spec: mongodumpJobSpec(rocket, backup.Spec.Target, fmt.Sprintf("%q", BackupArchiveName(backup)), nil),
+	}
+}
+
+// MongorestoreJob returns the job replacing the database of the rocket with the archive of the restore.
+// The archive only contains the rocketchat database, so the job authenticates against it instead of using a connection string.
+func MongorestoreJob(restore *chatv1alpha1.RocketRestore, rocket *chatv1alpha1.Rocket) *batchv1.Job {
+	source := restore.Spec.Source
+	archive := path.Join(MongodbBackupMountPath, source.Archive)
+	podSpec := corev1.PodSpec{Volumes: []corev1.Volume{claimVolume(source.ClaimName)}}
+	if source.S3 != nil {
+		// archives in a bucket are downloaded into an emptyDir first
+		archive = path.Join(MongodbBackupMountPath, path.Base(source.Archive))
+		download := fmt.Sprintf("set -eu\nmc cp %q %q\n", "target/"+strings.TrimPrefix(source.Archive, "/"), archive)
+		podSpec = corev1.PodSpec{
+			InitContainers: []corev1.Container{minioClientContainer("download", source.S3, download)},
+			Volumes:        []corev1.Volume{emptyDirVolume()},
+		}
+	}
+	host := fmt.Sprintf("rs0/%v:27017", rocket.Name+MongodbServiceSuffix)
+	script := fmt.Sprintf(`set -eu
+mongorestore --host=%q --username="$MONGODB_USER" --password="$MONGODB_PASSWORD" --authenticationDatabase=rocketchat \
+  --nsInclude='rocketchat.*' --drop --gzip --archive=%q`, host, archive)
+	if rocket.HasExternalDatabase() {
+		// the connection string of an external database is the only way to reach it
+		script = fmt.Sprintf(`set -eu
+mongorestore --uri="$MONGODB_URI" --nsInclude='rocketchat.*' --drop --gzip --archive=%q`, archive)
+	}
+
+	container := mongoToolsContainer(rocket, "restore", script)
+	if !rocket.HasExternalDatabase() {
+		container.Env = append(container.Env, authSecretEnvVar(rocket, "MONGODB_USER", "user"), authSecretEnvVar(rocket, "MONGODB_PASSWORD", "password"))
+	}
+	podSpec.Containers = []corev1.Container{container}
+	labels := backupLabels(rocket)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name + MongodbRestoreJobSuffix,
+			Namespace: restore.Namespace,
+			Labels:    labels,
+		},
+		Spec: backupJobSpec(labels, podSpec),
+	}
+}
+
+// mongodumpJobSpec dumps the database into the archive on the target and prunes old archives according to the retention.
+// archive is a shell word, so archive names can be generated when the job runs.
+// Archives on a claim are written directly, archives for a bucket are written to an emptyDir
+// and uploaded with the minio client afterwards.
+func mongodumpJobSpec(rocket *chatv1alpha1.Rocket, target chatv1alpha1.BackupTarget, archive string, retention *chatv1alpha1.BackupRetention) batchv1.JobSpec {
+	labels := backupLabels(rocket)
+	dump := fmt.Sprintf(`set -eu
+ARCHIVE=%v
+mongodump --uri="$MONGODB_URI" --gzip --archive="%v/$ARCHIVE"
+SIZE="$(stat -c %%s "%[2]v/$ARCHIVE")"
+`, archive, MongodbBackupMountPath)
+	result := `printf '{"archive": "%s", "size": %s}' "$ARCHIVE" "$SIZE"`
+
+	if target.S3 == nil {
+		script := dump + pruneClaimScript(rocket, retention) + result + " > /dev/termination-log"
+		return backupJobSpec(labels, corev1.PodSpec{
+			Containers: []corev1.Container{mongoToolsContainer(rocket, MongodbBackupComponentName, script)},
+			Volumes:    []corev1.Volume{claimVolume(target.ClaimName)},
+		})
+	}
+
+	bucketPath := path.Join(target.S3.Bucket, target.S3.Prefix)
+	dump += fmt.Sprintf(`ARCHIVE=%q/"$ARCHIVE"
+`, bucketPath) + result + " > " + path.Join(MongodbBackupMountPath, "result.json")
+	report := "cat " + path.Join(MongodbBackupMountPath, "result.json") + " > /dev/termination-log"
+	return backupJobSpec(labels, corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			mongoToolsContainer(rocket, MongodbBackupComponentName, dump),
+			uploadContainer(rocket, target.S3, retention),
+		},
+		Containers: []corev1.Container{mongoToolsContainer(rocket, "result", report)},
+		Volumes:    []corev1.Volume{emptyDirVolume()},
+	})
+}
+
+// scheduledArchivePattern matches the archives of scheduled backups, they are the only ones pruned
+func scheduledArchivePattern(rocket *chatv1alpha1.Rocket) string {
+	return rocket.Name + MongodbScheduledBackupPrefix + "*" + MongodbBackupArchiveSuffix
+}
+
+func pruneClaimScript(rocket *chatv1alpha1.Rocket, retention *chatv1alpha1.BackupRetention) string {
+	if retention == nil {
+		return ""
+	}
+	var script strings.Builder
+	find := fmt.Sprintf("find %v -maxdepth 1 -name '%v'", MongodbBackupMountPath, scheduledArchivePattern(rocket))
+	if retention.MaxAge != nil {
+		fmt.Fprintf(&script, "%v -mmin +%d -delete\n", find, int64(retention.MaxAge.Minutes()))
+	}
+	if retention.Count > 0 {
+		fmt.Fprintf(&script, "%v | sort | head -n -%d | xargs -r rm -f --\n", find, retention.Count)
+	}
+	return script.String()
+}
+
+// uploadContainer uploads the archive to the bucket and prunes the archives in it
+func uploadContainer(rocket *chatv1alpha1.Rocket, s3 *chatv1alpha1.S3Target, retention *chatv1alpha1.BackupRetention) corev1.Container {
+	folder := "target/" + path.Join(s3.Bucket, s3.Prefix) + "/"
+	var script strings.Builder
+	fmt.Fprintf(&script, "set -eu\nmc cp %v/*%v %q\n", MongodbBackupMountPath, MongodbBackupArchiveSuffix, folder)
+	if retention != nil {
+		find := fmt.Sprintf("mc find %q --name '%v'", folder, scheduledArchivePattern(rocket))
+		if retention.MaxAge != nil {
+			fmt.Fprintf(&script, "%v --older-than %dm --exec 'mc rm {}'\n", find, int64(retention.MaxAge.Minutes()))
+		}
+		if retention.Count > 0 {
+			fmt.Fprintf(&script, "%v | sort | head -n -%d | while read -r key; do mc rm \"$key\"; done\n", find, retention.Count)
+		}
+	}
+
+	return minioClientContainer("upload", s3, script.String())
+}
+
+// minioClientContainer runs the script with the minio client, the alias target points to the object storage
+func minioClientContainer(name string, s3 *chatv1alpha1.S3Target, script string) corev1.Container {
+	// the minio client reads the credentials of the alias target from MC_HOST_target
+	endpoint, err := url.Parse(s3.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{Scheme: "https", Host: s3.Endpoint}
+	}
+	credentials := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: s3.CredentialsSecret, Key: key},
+			},
+		}
+	}
+	return corev1.Container{
+		Name:    name,
+		Image:   MinioClientImage,
+		Command: []string{"/bin/sh", "-c", script},
+		Env: []corev1.EnvVar{
+			credentials("S3_ACCESS_KEY", "accessKey"),
+			credentials("S3_SECRET_KEY", "secretKey"),
+			{Name: "MC_CONFIG_DIR", Value: "/tmp/.mc"},
+			{Name: "MC_HOST_target", Value: fmt.Sprintf("%v://$(S3_ACCESS_KEY):$(S3_SECRET_KEY)@%v", endpoint.Scheme, endpoint.Host)},
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		VolumeMounts:             []corev1.VolumeMount{{Name: "target", MountPath: MongodbBackupMountPath}},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    &MongodbUser,
+			RunAsNonRoot: &boolTrue,
+		},
+	}
+}
+
+func backupLabels(rocket *chatv1alpha1.Rocket) map[string]string {
+	return util.MergeLabels(map[string]string{
+		"app":       rocket.Name,
+		"component": MongodbBackupComponentName,
+	}, rocket.Labels)
+}
+
+func backupJobSpec(labels map[string]string, podSpec corev1.PodSpec) batchv1.JobSpec {
+	backoffLimit := int32(1)
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	podSpec.SecurityContext = &corev1.PodSecurityContext{FSGroup: &MongodbUser}
+	return batchv1.JobSpec{
+		BackoffLimit: &backoffLimit,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       podSpec,
+		},
+	}
+}
+
+func emptyDirVolume() corev1.Volume {
+	return corev1.Volume{
+		Name:         "target",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
+
+func claimVolume(claimName string) corev1.Volume {
+	return corev1.Volume{
+		Name: "target",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+		},
+	}
+}
+
+// mongoToolsContainer runs the script in the mongodb image of the rocket.
+// The target is mounted at MongodbBackupMountPath and the connection string of the database is set as MONGODB_URI.
+func mongoToolsContainer(rocket *chatv1alpha1.Rocket, name, script string) corev1.Container {
+	return corev1.Container{
+		Name:    name,
+		Image:   MongodbImage + ":" + MongodbVersion(rocket),
+		Command: []string{"/bin/bash", "-c", script},
+		Env:     []corev1.EnvVar{authSecretEnvVar(rocket, "MONGODB_URI", "uri")},
+		// errors are reported with the tail of the logs
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		VolumeMounts:             []corev1.VolumeMount{{Name: "target", MountPath: MongodbBackupMountPath}},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    &MongodbUser,
+			RunAsNonRoot: &boolTrue,
+		},
+	}
+}
+
+func authSecretEnvVar(rocket *chatv1alpha1.Rocket, name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: DatabaseSecretReference(rocket),
+				Key:                  key,
+			},
+		},
+	}
+}
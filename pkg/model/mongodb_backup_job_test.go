@@ -0,0 +1,115 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMongodumpJob(t *testing.T) {
+	rocket := testRocket()
+	backup := &v1alpha1.RocketBackup{
+		ObjectMeta: v1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: v1alpha1.RocketBackupSpec{
+			Rocket: rocket.Name,
+			Target: v1alpha1.BackupTarget{ClaimName: "backups"},
+		},
+	}
+	job := MongodumpJob(backup, rocket)
+
+	if job.Name != "nightly-backup" {
+		t.Errorf("MongodumpJob() name = %v", job.Name)
+	}
+	pod := job.Spec.Template.Spec
+	if claim := pod.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != "backups" {
+		t.Errorf("MongodumpJob() doesn't mount the target claim: %v", pod.Volumes)
+	}
+	container := pod.Containers[0]
+	if container.Image != MongodbImage+":"+rocket.Spec.Database.Version {
+		t.Errorf("MongodumpJob() image = %v", container.Image)
+	}
+	if ref := container.Env[0].ValueFrom.SecretKeyRef; ref.Name != "test-mongodb-auth" || ref.Key != "uri" {
+		t.Errorf("MongodumpJob() doesn't use the auth secret: %v", ref)
+	}
+	if script := container.Command[2]; !strings.Contains(script, "ARCHIVE=\"nightly.archive.gz\"") {
+		t.Errorf("MongodumpJob() doesn't write the archive: %v", script)
+	}
+}
+
+func TestMongorestoreJob(t *testing.T) {
+	rocket := testRocket()
+	restore := &v1alpha1.RocketRestore{
+		ObjectMeta: v1.ObjectMeta{Name: "rollback", Namespace: "default"},
+		Spec: v1alpha1.RocketRestoreSpec{
+			Rocket: rocket.Name,
+			Source: v1alpha1.RestoreSource{ClaimName: "backups", Archive: "nightly.archive.gz"},
+		},
+	}
+	job := MongorestoreJob(restore, rocket)
+
+	if job.Name != "rollback-restore" {
+		t.Errorf("MongorestoreJob() name = %v", job.Name)
+	}
+	script := job.Spec.Template.Spec.Containers[0].Command[2]
+	for _, want := range []string{"--drop", "--archive=\"/backup/nightly.archive.gz\"", "rs0/test-mongodb-service:27017"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("MongorestoreJob() script doesn't contain %v: %v", want, script)
+		}
+	}
+}
+
+func TestMongorestoreJobFromBucket(t *testing.T) {
+	rocket := testRocket()
+	restore := &v1alpha1.RocketRestore{
+		ObjectMeta: v1.ObjectMeta{Name: "rollback", Namespace: "default"},
+		Spec: v1alpha1.RocketRestoreSpec{
+			Rocket: rocket.Name,
+			Source: v1alpha1.RestoreSource{
+				S3: &v1alpha1.S3Target{
+					Endpoint:          "http://minio.minio:9000",
+					Bucket:            "backups",
+					CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+				},
+				Archive: "backups/chat/nightly.archive.gz",
+			},
+		},
+	}
+	pod := MongorestoreJob(restore, rocket).Spec.Template.Spec
+
+	if len(pod.InitContainers) != 1 || !strings.Contains(pod.InitContainers[0].Command[2], "\"target/backups/chat/nightly.archive.gz\" \"/backup/nightly.archive.gz\"") {
+		t.Errorf("MongorestoreJob() doesn't download the archive: %v", pod.InitContainers)
+	}
+	if pod.Volumes[0].EmptyDir == nil {
+		t.Errorf("MongorestoreJob() doesn't download into an emptyDir: %v", pod.Volumes)
+	}
+	if script := pod.Containers[0].Command[2]; !strings.Contains(script, "--archive=\"/backup/nightly.archive.gz\"") {
+		t.Errorf("MongorestoreJob() doesn't restore the downloaded archive: %v", script)
+	}
+}
+
+func TestParseBackupResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    int64
+		wantErr bool
+	}{
+		{name: "size", message: `{"size": 1024}`, want: 1024},
+		{name: "empty message", message: "", wantErr: true},
+		{name: "log output", message: "Failed: error connecting to db server", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBackupResult(tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBackupResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Size != tt.want {
+				t.Errorf("ParseBackupResult() size = %v, want %v", got.Size, tt.want)
+			}
+		})
+	}
+}
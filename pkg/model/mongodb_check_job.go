@@ -0,0 +1,86 @@
+package model
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// connectivityCheckScript pings the database with both connection strings Rocket.Chat uses
+const connectivityCheckScript = `set -eu
+MONGO_SHELL="mongosh"
+command -v mongosh > /dev/null || MONGO_SHELL="mongo"
+for URI in "$MONGODB_URI" "$MONGODB_OPLOG_URI"; do
+  $MONGO_SHELL --quiet "$URI" --eval 'var res = db.runCommand({ping: 1}); if (!res.ok) { throw new Error(res.errmsg) }'
+done
+echo "database is reachable"`
+
+// MongodbCheckJobCreator creates the job checking the connectivity to an external database.
+// The database is ready once the job succeeded.
+type MongodbCheckJobCreator struct{}
+
+// Name returns the ressource action of the MongodbCheckJobCreator
+func (c *MongodbCheckJobCreator) Name() string {
+	return "Mongodb Connectivity Check Job"
+}
+
+func (c *MongodbCheckJobCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	labels := util.MergeLabels(map[string]string{
+		"app":       rocket.Name,
+		"component": MongodbCheckComponentName,
+	}, rocket.Labels)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rocket.Name + MongodbCheckJobSuffix,
+			Namespace: rocket.Namespace,
+			Labels:    labels,
+		},
+		Spec: backupJobSpec(labels, corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "connectivity-check",
+				Image:   MongodbImage + ":" + MongodbVersion(rocket),
+				Command: []string{"/bin/bash", "-c", connectivityCheckScript},
+				Env: []corev1.EnvVar{
+					authSecretEnvVar(rocket, "MONGODB_URI", "uri"),
+					authSecretEnvVar(rocket, "MONGODB_OPLOG_URI", "oplog-uri"),
+				},
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				SecurityContext: &corev1.SecurityContext{
+					RunAsUser:    &MongodbUser,
+					RunAsNonRoot: &boolTrue,
+				},
+			}},
+		}),
+	}
+}
+
+func (c *MongodbCheckJobCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name + MongodbCheckJobSuffix,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *MongodbCheckJobCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// the template of a job is immutable, finished checks are deleted by the controller and created again
+	drifted := DriftedFields(desired, cur, "spec")
+	if len(drifted) == 0 {
+		return cur, nil
+	}
+	job := desired.(*batchv1.Job)
+	job.Spec = cur.(*batchv1.Job).Spec
+	return job, drifted
+}
+
+// DependsOn returns the creators of the resources the MongodbCheckJobCreator depends on
+func (c *MongodbCheckJobCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, the check job is only added for an external database
+func (c *MongodbCheckJobCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
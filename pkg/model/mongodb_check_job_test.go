@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDatabaseSecretReference(t *testing.T) {
+	rocket := testRocket()
+	if ref := DatabaseSecretReference(rocket); ref.Name != "test-mongodb-auth" {
+		t.Errorf("DatabaseSecretReference() = %v, want the auth secret", ref.Name)
+	}
+	rocket.Spec.Database.External = &v1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "atlas"}}
+	if ref := DatabaseSecretReference(rocket); ref.Name != "atlas" {
+		t.Errorf("DatabaseSecretReference() = %v, want the external secret", ref.Name)
+	}
+}
+
+func TestMongodbCheckJobCreator(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Database.External = &v1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "atlas"}}
+	creator := new(MongodbCheckJobCreator)
+	job := creator.CreateResource(rocket).(*batchv1.Job)
+
+	if job.Name != "test-mongodb-check" {
+		t.Errorf("CreateResource() name = %v", job.Name)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	keys := map[string]bool{}
+	for _, env := range container.Env {
+		if ref := env.ValueFrom.SecretKeyRef; ref.Name == "atlas" {
+			keys[ref.Key] = true
+		}
+	}
+	if !keys["uri"] || !keys["oplog-uri"] {
+		t.Errorf("CreateResource() doesn't check both connection strings: %v", container.Env)
+	}
+
+	// the template of the live job is immutable and never drifts
+	live := job.DeepCopy()
+	live.Spec.Template.Spec.Containers[0].Image = "mongo:5.0"
+	if _, drifted := creator.Update(job, live); len(drifted) > 0 {
+		t.Errorf("Update() drifted = %v, want none", drifted)
+	}
+}
@@ -2,7 +2,6 @@ package model
 
 import (
 	"fmt"
-	"reflect"
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -47,14 +46,8 @@ exec /opt/bitnami/scripts/mongodb/entrypoint.sh /opt/bitnami/scripts/mongodb/run
 	}
 	return cm
 }
-func (c *MongodbScriptsConfigmapCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-	cm := cur.(*corev1.ConfigMap)
-	if !reflect.DeepEqual(cm.Labels, rocket.Labels) {
-		cm.Labels = rocket.Labels
-		update = true
-	}
-	return cm, update
+func (c *MongodbScriptsConfigmapCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
 }
 
 func (c *MongodbScriptsConfigmapCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKey {
@@ -63,3 +56,13 @@ func (c *MongodbScriptsConfigmapCreator) Selector(r *chatv1alpha1.Rocket) client
 		Namespace: r.Namespace,
 	}
 }
+
+// DependsOn returns the creators of the resources the MongodbScriptsConfigmapCreator depends on
+func (c *MongodbScriptsConfigmapCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, the scripts are only added for a database deployed by the operator
+func (c *MongodbScriptsConfigmapCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
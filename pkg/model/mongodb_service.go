@@ -1,8 +1,6 @@
 package model
 
 import (
-	"reflect"
-
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -15,8 +13,11 @@ type MongodbServiceCreator struct {
 	Headless bool
 }
 
-// Name returns the ressource action of the MongodbAuthSecretCreator
-func (m *MongodbServiceCreator) Name() string {
+// Name returns the ressource action of the MongodbServiceCreator
+func (c *MongodbServiceCreator) Name() string {
+	if c.Headless {
+		return "Mongodb Headless Service"
+	}
 	return "Mongodb Service"
 }
 func (c *MongodbServiceCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
@@ -57,13 +58,16 @@ func (c *MongodbServiceCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKe
 	return key
 }
 
-func (c *MongodbServiceCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	service := cur.(*corev1.Service)
-	labels := util.MergeLabels(mongodbStatefulSetLabels(rocket), rocket.Labels)
-	if reflect.DeepEqual(service.Labels, labels) {
-		return cur, false
-	}
-	service.Labels = labels
-	service.Spec.Selector = labels
-	return service, true
+func (c *MongodbServiceCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return updateService(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the MongodbServiceCreator depends on
+func (c *MongodbServiceCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, the services are only added for a database deployed by the operator
+func (c *MongodbServiceCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
 }
@@ -1,8 +1,6 @@
 package model
 
 import (
-	"reflect"
-
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,40 +16,14 @@ type MongodbStatefulSetCreator struct{}
 func (c *MongodbStatefulSetCreator) Name() string {
 	return "Mongodb StatefulSet"
 }
-func (c *MongodbStatefulSetCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-	sts := cur.(*appsv1.StatefulSet)
-
-	// check labels
-	d := rocket.Spec.Database
-	if !reflect.DeepEqual(sts.Labels, rocket.Labels) {
-		sts.Labels = rocket.Labels
-		update = true
-	}
-
-	// check image
-	curImage := sts.Spec.Template.Spec.Containers[0].Image
-	newImage := "docker.io/bitnami/mongodb:" + d.Version
-	if curImage != newImage {
-		sts.Spec.Template.Spec.Containers[0].Image = newImage
-		update = true
-	}
-
-	// check replicas
-	curReplicas := sts.Spec.Replicas
-	if *curReplicas != d.Replicas && d.Replicas > 0 {
-		sts.Spec.Replicas = &d.Replicas
-		update = true
-	}
-
-	// check storageSpec
-	copy := sts.DeepCopy()
-	createStatefulSetVolumes(rocket, d.StorageSpec, sts)
-	if !reflect.DeepEqual(copy.Spec.VolumeClaimTemplates[0].Spec, sts.Spec.VolumeClaimTemplates[0].Spec) {
-		update = true
-	}
-
-	return sts, update
+func (c *MongodbStatefulSetCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// selector and volumeClaimTemplates of a statefulSet are immutable
+	drifted := DriftedFields(desired, cur, "spec.selector", "spec.volumeClaimTemplates")
+	sts, curSts := desired.(*appsv1.StatefulSet), cur.(*appsv1.StatefulSet)
+	drifted = podTemplateDrift(drifted, &sts.Spec.Template.Spec, &curSts.Spec.Template.Spec)
+	sts.Spec.Selector = curSts.Spec.Selector
+	sts.Spec.VolumeClaimTemplates = curSts.Spec.VolumeClaimTemplates
+	return sts, drifted
 }
 
 func (c *MongodbStatefulSetCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
@@ -80,7 +52,7 @@ func (c *MongodbStatefulSetCreator) CreateResource(rocket *chatv1alpha1.Rocket)
 					Containers: []corev1.Container{
 						{
 							Name:    "mongodb",
-							Image:   "docker.io/bitnami/mongodb:" + d.Version,
+							Image:   MongodbImage + ":" + MongodbVersion(rocket),
 							Command: []string{MongodbScriptPath},
 							Ports: []corev1.ContainerPort{
 								{
@@ -124,6 +96,7 @@ func (c *MongodbStatefulSetCreator) CreateResource(rocket *chatv1alpha1.Rocket)
 	if replicas > 0 {
 		sts.Spec.Replicas = &replicas
 	}
+	applyPodTemplateOverrides(&sts.Spec.Template.Spec, d.PodTemplate)
 
 	// Create volumes
 	createStatefulSetVolumes(rocket, d.StorageSpec, sts)
@@ -149,17 +122,6 @@ func createStatefulSetVolumes(rocket *chatv1alpha1.Rocket, claimTemplate *chatv1
 
 	var volumeSource corev1.VolumeSource
 
-	if claimTemplate.Name == "" {
-		claimTemplate.Name = rocket.Name + MongodbVolumeSuffix
-	}
-	if claimTemplate.Spec.AccessModes == nil {
-		claimTemplate.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
-	}
-	if claimTemplate.Spec.VolumeMode == nil {
-		defaultVolumeMode := corev1.PersistentVolumeFilesystem
-		claimTemplate.Spec.VolumeMode = &defaultVolumeMode
-	}
-
 	pvcTemplate := VolumeClaimTemplate(claimTemplate)
 	volumeSource.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
 		ClaimName: rocket.Name + MongodbVolumeSuffix,
@@ -183,7 +145,7 @@ func (c *MongodbStatefulSetCreator) Selector(r *chatv1alpha1.Rocket) client.Obje
 func mongodbStatefulsetHealthChecks() (liveness, readiness *corev1.Probe) {
 	liveness = &corev1.Probe{
 		Handler: corev1.Handler{Exec: &corev1.ExecAction{Command: []string{
-			"mongo", "--disableImplicitSessions", "--eval", "db.adminCommand('ping')",
+			"bash", "-ec", MongodbLivenessCommand,
 		}}},
 		InitialDelaySeconds: 30,
 	}
@@ -282,3 +244,18 @@ func mongodbEnvVars(r *chatv1alpha1.Rocket) []corev1.EnvVar {
 		},
 	}
 }
+
+// DependsOn returns the creators of the resources the MongodbStatefulSetCreator depends on
+func (c *MongodbStatefulSetCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{
+		new(ServiceAccountCreator),
+		new(MongodbAuthSecretCreator),
+		new(MongodbScriptsConfigmapCreator),
+		&MongodbServiceCreator{Headless: true},
+	}
+}
+
+// IsWanted returns true, the statefulSet is only added for a database deployed by the operator
+func (c *MongodbStatefulSetCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
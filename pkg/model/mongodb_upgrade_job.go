@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// featureCompatibilityScript waits until every member of the replica set is healthy and raises the featureCompatibilityVersion.
+// The mongo shell exits with an error if the command fails, so the job fails with the tail of the logs.
+const featureCompatibilityScript = `set -eu
+MONGO_SHELL="mongosh"
+command -v mongosh > /dev/null || MONGO_SHELL="mongo --disableImplicitSessions"
+mongo_eval() {
+  $MONGO_SHELL --quiet --host=%q --username=root --password="$MONGODB_ROOT_PASSWORD" --authenticationDatabase=admin --eval "$1"
+}
+for attempt in $(seq 1 60); do
+  HEALTHY="$(mongo_eval 'rs.status().members.every(function(m) { return m.health === 1 && (m.state === 1 || m.state === 2) })' || true)"
+  [ "$HEALTHY" = "true" ] && break
+  [ "$attempt" -eq 60 ] && { echo "replica set didn't become healthy"; exit 1; }
+  echo "waiting for a healthy replica set"
+  sleep 5
+done
+mongo_eval 'var res = db.adminCommand(%v); if (!res.ok) { throw new Error(res.errmsg) }'
+echo "featureCompatibilityVersion is %v"`
+
+// MongodbFeatureCompatibilityJob returns the job setting the featureCompatibilityVersion of the database
+// to the release series of the version the database was upgraded to
+func MongodbFeatureCompatibilityJob(rocket *chatv1alpha1.Rocket, version string) (*batchv1.Job, error) {
+	series, err := MongodbReleaseSeries(version)
+	if err != nil {
+		return nil, err
+	}
+	command := fmt.Sprintf(`{setFeatureCompatibilityVersion: "%v"}`, series)
+	if major, _ := strconv.Atoi(strings.Split(series, ".")[0]); major >= 7 {
+		// starting with 7.0 the command has to be confirmed, the featureCompatibilityVersion can't be lowered afterwards
+		command = fmt.Sprintf(`{setFeatureCompatibilityVersion: "%v", confirm: true}`, series)
+	}
+	host := fmt.Sprintf("rs0/%v:27017", rocket.Name+MongodbServiceSuffix)
+	script := fmt.Sprintf(featureCompatibilityScript, host, command, series)
+
+	labels := util.MergeLabels(map[string]string{
+		"app":       rocket.Name,
+		"component": MongodbUpgradeComponentName,
+	}, rocket.Labels)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rocket.Name + MongodbUpgradeJobSuffix,
+			Namespace: rocket.Namespace,
+			Labels:    labels,
+		},
+		Spec: backupJobSpec(labels, corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:                     "feature-compatibility",
+				Image:                    MongodbImage + ":" + version,
+				Command:                  []string{"/bin/bash", "-c", script},
+				Env:                      []corev1.EnvVar{authSecretEnvVar(rocket, "MONGODB_ROOT_PASSWORD", "root-password")},
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				SecurityContext: &corev1.SecurityContext{
+					RunAsUser:    &MongodbUser,
+					RunAsNonRoot: &boolTrue,
+				},
+			}},
+		}),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+var (
+	// mongodbReleaseSeries are the release series of mongodb in upgrade order.
+	// Major upgrades have to pass through every series, a mongod can't start on a data directory written two series before.
+	mongodbReleaseSeries = []string{"3.6", "4.0", "4.2", "4.4", "5.0", "6.0", "7.0"}
+	// mongodbSeriesRegex matches the release series of a bitnami/mongodb tag, e.g. 4.4 in 4.4.10-debian-10-r20
+	mongodbSeriesRegex = regexp.MustCompile(`^([0-9]+\.[0-9]+)(\.[0-9]+)?(-[0-9A-Za-z.-]+)?$`)
+)
+
+// MongodbReleaseSeries returns the release series of a bitnami/mongodb tag, which is also its featureCompatibilityVersion
+func MongodbReleaseSeries(version string) (string, error) {
+	match := mongodbSeriesRegex.FindStringSubmatch(version)
+	if match == nil {
+		return "", fmt.Errorf("%q is not a mongodb version", version)
+	}
+	return match[1], nil
+}
+
+func mongodbSeriesIndex(version string) (int, error) {
+	series, err := MongodbReleaseSeries(version)
+	if err != nil {
+		return 0, err
+	}
+	for i, s := range mongodbReleaseSeries {
+		if s == series {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("mongodb %v is not a supported release series", series)
+}
+
+// IsMongodbMajorUpgrade returns true if the database is upgraded to the next release series
+// and false if both versions belong to the same series.
+// Upgrades skipping a series and downgrades to an older series are refused with an error.
+func IsMongodbMajorUpgrade(from, to string) (bool, error) {
+	if from == to {
+		return false, nil
+	}
+	fromIndex, err := mongodbSeriesIndex(from)
+	if err != nil {
+		return false, err
+	}
+	toIndex, err := mongodbSeriesIndex(to)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case toIndex == fromIndex:
+		return false, nil
+	case toIndex == fromIndex+1:
+		return true, nil
+	case toIndex < fromIndex:
+		return false, fmt.Errorf("downgrading mongodb from %v to %v is not supported", from, to)
+	default:
+		return false, fmt.Errorf("upgrading mongodb from %v to %v skips a major version, upgrade to %v first",
+			from, to, mongodbReleaseSeries[fromIndex+1])
+	}
+}
+
+// MongodbVersion returns the version of mongodb deployed for the rocket.
+// Versions of the release series running on the data directory are rolled out directly,
+// the next series only once the controller started a major upgrade in the status.
+// A failed upgrade keeps the new version, the replica set isn't rolled back without a change of the spec.
+func MongodbVersion(rocket *chatv1alpha1.Rocket) string {
+	if rocket.IsDatabaseUpgrading() || rocket.IsDatabaseUpgradeFailed() {
+		return rocket.Status.DatabaseUpgrade.ToVersion
+	}
+	running, wanted := rocket.Status.DatabaseVersion, rocket.Spec.Database.Version
+	if running == "" {
+		return wanted
+	}
+	if major, err := IsMongodbMajorUpgrade(running, wanted); err != nil || major {
+		return running
+	}
+	return wanted
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+func TestIsMongodbMajorUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		wantMajor bool
+		wantErr   bool
+	}{
+		{name: "same version", from: "4.4.10", to: "4.4.10"},
+		{name: "patch upgrade", from: "4.4.10", to: "4.4.11-debian-10-r0"},
+		{name: "patch downgrade", from: "4.4.10", to: "4.4.8"},
+		{name: "next series", from: "4.4.10", to: "5.0.3", wantMajor: true},
+		{name: "next series before 5.0", from: "4.0.27", to: "4.2.17", wantMajor: true},
+		{name: "skipping a series", from: "4.4.10", to: "6.0.2", wantErr: true},
+		{name: "downgrading a series", from: "5.0.3", to: "4.4.10", wantErr: true},
+		{name: "unknown series", from: "4.4.10", to: "4.5.0", wantErr: true},
+		{name: "malformed version", from: "4.4.10", to: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			major, err := IsMongodbMajorUpgrade(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsMongodbMajorUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if major != tt.wantMajor {
+				t.Errorf("IsMongodbMajorUpgrade() = %v, want %v", major, tt.wantMajor)
+			}
+		})
+	}
+}
+
+func TestMongodbVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		running string
+		upgrade *v1alpha1.DatabaseUpgradeStatus
+		wanted  string
+		want    string
+	}{
+		{name: "new rocket", wanted: "4.4.10", want: "4.4.10"},
+		{name: "patch upgrade", running: "4.4.8", wanted: "4.4.10", want: "4.4.10"},
+		{name: "major upgrade not started", running: "4.4.10", wanted: "5.0.3", want: "4.4.10"},
+		{name: "skipping a series", running: "4.4.10", wanted: "6.0.2", want: "4.4.10"},
+		{
+			name:    "major upgrade in progress",
+			running: "4.4.10",
+			upgrade: &v1alpha1.DatabaseUpgradeStatus{ToVersion: "5.0.3", Phase: v1alpha1.DatabaseUpgradeRollingOut},
+			wanted:  "6.0.2",
+			want:    "5.0.3",
+		},
+		{
+			name:    "major upgrade completed",
+			running: "5.0.3",
+			upgrade: &v1alpha1.DatabaseUpgradeStatus{ToVersion: "5.0.3", Phase: v1alpha1.DatabaseUpgradeCompleted},
+			wanted:  "5.0.5",
+			want:    "5.0.5",
+		},
+		{
+			name:    "major upgrade failed",
+			running: "4.4.10",
+			upgrade: &v1alpha1.DatabaseUpgradeStatus{ToVersion: "5.0.3", Phase: v1alpha1.DatabaseUpgradeFailed},
+			wanted:  "5.0.3",
+			want:    "5.0.3",
+		},
+		{
+			name:    "failed major upgrade rolled back",
+			running: "4.4.10",
+			upgrade: &v1alpha1.DatabaseUpgradeStatus{ToVersion: "5.0.3", Phase: v1alpha1.DatabaseUpgradeFailed},
+			wanted:  "4.4.10",
+			want:    "4.4.10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.Database.Version = tt.wanted
+			rocket.Status.DatabaseVersion = tt.running
+			rocket.Status.DatabaseUpgrade = tt.upgrade
+			if got := MongodbVersion(rocket); got != tt.want {
+				t.Errorf("MongodbVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMongodbFeatureCompatibilityJob(t *testing.T) {
+	tests := []struct {
+		version string
+		command string
+	}{
+		{version: "5.0.3", command: `{setFeatureCompatibilityVersion: "5.0"}`},
+		{version: "7.0.2-debian-11-r0", command: `{setFeatureCompatibilityVersion: "7.0", confirm: true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			job, err := MongodbFeatureCompatibilityJob(testRocket(), tt.version)
+			if err != nil {
+				t.Fatalf("MongodbFeatureCompatibilityJob() error = %v", err)
+			}
+			container := job.Spec.Template.Spec.Containers[0]
+			if container.Image != MongodbImage+":"+tt.version {
+				t.Errorf("MongodbFeatureCompatibilityJob() image = %v", container.Image)
+			}
+			if script := container.Command[2]; !strings.Contains(script, tt.command) {
+				t.Errorf("MongodbFeatureCompatibilityJob() script doesn't contain %v:\n%v", tt.command, script)
+			}
+		})
+	}
+}
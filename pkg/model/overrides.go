@@ -0,0 +1,176 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// protectedOverridePaths are the fields a patch of an override can't change,
+// the operator finds its resources by their names and the resources find their pods by their selectors
+var protectedOverridePaths = [][]string{
+	{"metadata", "name"},
+	{"metadata", "namespace"},
+	{"metadata", "ownerReferences"},
+	{"spec", "selector"},
+}
+
+// ApplyOverrides applies the overrides of the rocket matching the resource to it in the order of the spec
+func ApplyOverrides(rocket *chatv1alpha1.Rocket, obj client.Object) (client.Object, error) {
+	for i, override := range rocket.Spec.Overrides {
+		if !overrideMatches(rocket, override, obj) {
+			continue
+		}
+		patched, err := applyOverride(override, obj)
+		if err != nil {
+			return nil, fmt.Errorf("Error applying override %d to %v %v: %w", i, override.Kind, obj.GetName(), err)
+		}
+		obj = patched
+	}
+	return obj, nil
+}
+
+// ValidateOverride checks that the patch of the override can be decoded and doesn't touch protected fields
+func ValidateOverride(override chatv1alpha1.ResourceOverride) error {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return fmt.Errorf("patch is neither YAML nor JSON: %w", err)
+	}
+	if override.Type == chatv1alpha1.OverrideJSONPatch {
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return fmt.Errorf("patch isn't a list of JSON patch operations: %w", err)
+		}
+		for _, operation := range operations {
+			path, err := operation.Path()
+			if err != nil {
+				return err
+			}
+			if protected := protectedPath(strings.Split(strings.TrimPrefix(path, "/"), "/")); protected != "" {
+				return fmt.Errorf("patch can't change %v", protected)
+			}
+		}
+		return nil
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &object); err != nil {
+		return fmt.Errorf("strategic merge patch isn't an object: %w", err)
+	}
+	for _, path := range protectedOverridePaths {
+		if _, found, _ := unstructured.NestedFieldNoCopy(object, path...); found {
+			return fmt.Errorf("patch can't change %v", strings.Join(path, "."))
+		}
+	}
+	return nil
+}
+
+func overrideMatches(rocket *chatv1alpha1.Rocket, override chatv1alpha1.ResourceOverride, obj client.Object) bool {
+	if override.Kind != kindOf(obj) {
+		return false
+	}
+	return override.NameSuffix == "" || obj.GetName() == rocket.Name+override.NameSuffix
+}
+
+// kindOf returns the kind of the resource, typed resources of the creators don't have their TypeMeta set
+func kindOf(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
+
+// applyOverride patches a copy of the resource and rejects patches changing protected fields
+func applyOverride(override chatv1alpha1.ResourceOverride, obj client.Object) (client.Object, error) {
+	if err := ValidateOverride(override); err != nil {
+		return nil, err
+	}
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch {
+	case override.Type == chatv1alpha1.OverrideJSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		patched, err = operations.Apply(original)
+		if err != nil {
+			return nil, err
+		}
+	case isUnstructured(obj):
+		// resources without a go type don't have patch strategies, their lists are replaced
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// decode into an empty resource, so fields removed by the patch aren't left over from the original
+	result := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if err := json.Unmarshal(patched, result); err != nil {
+		return nil, fmt.Errorf("patched resource is invalid: %w", err)
+	}
+	if err := checkProtectedFields(obj, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func isUnstructured(obj client.Object) bool {
+	_, ok := obj.(*unstructured.Unstructured)
+	return ok
+}
+
+// checkProtectedFields compares the protected fields of the resource before and after the patch
+func checkProtectedFields(original, patched client.Object) error {
+	originalMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+	if err != nil {
+		return err
+	}
+	patchedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(patched)
+	if err != nil {
+		return err
+	}
+	for _, path := range protectedOverridePaths {
+		originalValue, _, _ := unstructured.NestedFieldNoCopy(originalMap, path...)
+		patchedValue, _, _ := unstructured.NestedFieldNoCopy(patchedMap, path...)
+		if !equality.Semantic.DeepEqual(originalValue, patchedValue) {
+			return fmt.Errorf("patch can't change %v", strings.Join(path, "."))
+		}
+	}
+	return nil
+}
+
+// protectedPath returns the protected field the path of a JSON patch operation points into, empty if there is none.
+// Operations on a parent of a protected field are caught when the patched resource is compared with the original.
+func protectedPath(path []string) string {
+	for _, protected := range protectedOverridePaths {
+		if len(path) >= len(protected) && reflect.DeepEqual(path[:len(protected)], protected) {
+			return strings.Join(protected, ".")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		override v1alpha1.ResourceOverride
+		check    func(t *testing.T, obj client.Object)
+		wantErr  bool
+	}{
+		{
+			name: "strategic merge adds a sidecar",
+			override: v1alpha1.ResourceOverride{
+				Kind:       "Deployment",
+				NameSuffix: RocketWebserverDeploymentSuffix,
+				Patch: `
+spec:
+  template:
+    spec:
+      hostAliases:
+      - ip: 10.0.0.1
+        hostnames: [smtp.internal]
+      containers:
+      - name: proxy
+        image: nginx`,
+			},
+			check: func(t *testing.T, obj client.Object) {
+				pod := obj.(*appsv1.Deployment).Spec.Template.Spec
+				images := map[string]string{}
+				for _, container := range pod.Containers {
+					images[container.Name] = container.Image
+				}
+				if len(images) != 2 || images["rocket"] == "" || images["proxy"] != "nginx" {
+					t.Errorf("ApplyOverrides() containers = %v, want rocket and proxy", images)
+				}
+				if len(pod.HostAliases) != 1 {
+					t.Errorf("ApplyOverrides() hostAliases = %v", pod.HostAliases)
+				}
+			},
+		},
+		{
+			name: "json patch",
+			override: v1alpha1.ResourceOverride{
+				Kind:  "Deployment",
+				Type:  v1alpha1.OverrideJSONPatch,
+				Patch: `[{"op": "add", "path": "/metadata/annotations", "value": {"team": "chat"}}]`,
+			},
+			check: func(t *testing.T, obj client.Object) {
+				if obj.GetAnnotations()["team"] != "chat" {
+					t.Errorf("ApplyOverrides() annotations = %v", obj.GetAnnotations())
+				}
+			},
+		},
+		{
+			name:     "other kind",
+			override: v1alpha1.ResourceOverride{Kind: "Service", Patch: `{"metadata": {"annotations": {"team": "chat"}}}`},
+			check: func(t *testing.T, obj client.Object) {
+				if len(obj.GetAnnotations()) != 0 {
+					t.Errorf("ApplyOverrides() patched a Deployment with an override of a Service")
+				}
+			},
+		},
+		{
+			name: "changing the selector through a parent",
+			override: v1alpha1.ResourceOverride{
+				Kind:  "Deployment",
+				Type:  v1alpha1.OverrideJSONPatch,
+				Patch: `[{"op": "replace", "path": "/spec", "value": {}}]`,
+			},
+			wantErr: true,
+		},
+		{
+			name:     "renaming",
+			override: v1alpha1.ResourceOverride{Kind: "Deployment", Patch: `{"metadata": {"name": "other"}}`},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.Overrides = []v1alpha1.ResourceOverride{tt.override}
+			obj, err := ApplyOverrides(rocket, new(RocketDeploymentCreator).CreateResource(rocket))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, obj)
+			}
+		})
+	}
+}
+
+func TestApplyOverridesKeepsOtherResources(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Overrides = []v1alpha1.ResourceOverride{{Kind: "Secret", NameSuffix: "-other", Patch: `{"metadata": {"labels": {"a": "b"}}}`}}
+	secret := new(RocketAdminSecretCreator).CreateResource(rocket)
+	obj, err := ApplyOverrides(rocket, secret)
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+	if obj != secret || obj.(*corev1.Secret).Labels["a"] != "" {
+		t.Errorf("ApplyOverrides() patched a resource with another name suffix")
+	}
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// applyPodTemplateOverrides sets the scheduling of the pod and replaces the resources of its first container
+func applyPodTemplateOverrides(pod *corev1.PodSpec, overrides chatv1alpha1.PodTemplateOverrides) {
+	if overrides.Resources != nil {
+		pod.Containers[0].Resources = *overrides.Resources.DeepCopy()
+	}
+	pod.Affinity = overrides.Affinity.DeepCopy()
+	pod.NodeSelector = overrides.NodeSelector
+	pod.PriorityClassName = overrides.PriorityClassName
+	for _, toleration := range overrides.Tolerations {
+		pod.Tolerations = append(pod.Tolerations, *toleration.DeepCopy())
+	}
+	for _, constraint := range overrides.TopologySpreadConstraints {
+		pod.TopologySpreadConstraints = append(pod.TopologySpreadConstraints, *constraint.DeepCopy())
+	}
+}
+
+// podTemplateDrift compares the overridable fields and the settings sources of the pod templates.
+// Unlike DriftedFields it also reports fields which are set in the live template but were removed from the desired template,
+// the api server doesn't default any of them in templates.
+// Fields are only added to drifted if neither they nor one of their children were reported already.
+func podTemplateDrift(drifted []string, desired, cur *corev1.PodSpec) []string {
+	const path = "spec.template.spec"
+	type podField struct {
+		path          string
+		desired, live interface{}
+	}
+	fields := []podField{
+		{path + ".affinity", desired.Affinity, cur.Affinity},
+		{path + ".nodeSelector", desired.NodeSelector, cur.NodeSelector},
+		{path + ".priorityClassName", desired.PriorityClassName, cur.PriorityClassName},
+		{path + ".tolerations", desired.Tolerations, cur.Tolerations},
+		{path + ".topologySpreadConstraints", desired.TopologySpreadConstraints, cur.TopologySpreadConstraints},
+	}
+	for i, container := range desired.Containers {
+		// containers added by overrides might be ordered before the containers of the creators
+		for _, live := range cur.Containers {
+			if live.Name == container.Name {
+				fields = append(fields,
+					podField{fmt.Sprintf("%v.containers[%d].resources", path, i), container.Resources, live.Resources},
+					// removed settings sources
+					podField{fmt.Sprintf("%v.containers[%d].envFrom", path, i), container.EnvFrom, live.EnvFrom},
+				)
+			}
+		}
+	}
+
+	for _, field := range fields {
+		if equality.Semantic.DeepEqual(field.desired, field.live) || isDrifted(drifted, field.path) {
+			continue
+		}
+		drifted = append(drifted, field.path)
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+func isDrifted(drifted []string, path string) bool {
+	for _, d := range drifted {
+		if d == path || strings.HasPrefix(d, path+".") || strings.HasPrefix(d, path+"[") {
+			return true
+		}
+	}
+	return false
+}
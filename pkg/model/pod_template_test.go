@@ -0,0 +1,81 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodTemplateOverrides(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.PodTemplate = v1alpha1.PodTemplateOverrides{
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+		Tolerations:       []corev1.Toleration{{Key: "dedicated", Value: "chat", Effect: corev1.TaintEffectNoSchedule}},
+		NodeSelector:      map[string]string{"pool": "chat"},
+		PriorityClassName: "high",
+	}
+	pod := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment).Spec.Template.Spec
+
+	if limit := pod.Containers[0].Resources.Limits.Memory(); limit.String() != "2Gi" {
+		t.Errorf("CreateResource() memory limit = %v", limit)
+	}
+	if len(pod.Containers[0].Resources.Requests) != 0 {
+		t.Errorf("CreateResource() kept the default requests: %v", pod.Containers[0].Resources.Requests)
+	}
+	if len(pod.Tolerations) != 1 || pod.NodeSelector["pool"] != "chat" || pod.PriorityClassName != "high" {
+		t.Errorf("CreateResource() doesn't schedule the pods as configured: %v, %v, %v", pod.Tolerations, pod.NodeSelector, pod.PriorityClassName)
+	}
+
+	sts := new(MongodbStatefulSetCreator).CreateResource(rocket).(*appsv1.StatefulSet)
+	if requests := sts.Spec.Template.Spec.Containers[0].Resources.Requests; requests.Memory().String() != "1500Mi" {
+		t.Errorf("webserver overrides changed the database requests: %v", requests)
+	}
+}
+
+func TestPodTemplateDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(live *corev1.PodSpec)
+		drifted []string
+		want    []string
+	}{
+		{
+			name:   "no drift",
+			mutate: func(live *corev1.PodSpec) {},
+		},
+		{
+			name:   "removed toleration",
+			mutate: func(live *corev1.PodSpec) { live.Tolerations = []corev1.Toleration{{Key: "dedicated"}} },
+			want:   []string{"spec.template.spec.tolerations"},
+		},
+		{
+			name: "changed requests",
+			mutate: func(live *corev1.PodSpec) {
+				live.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+			},
+			want: []string{"spec.template.spec.containers[0].resources"},
+		},
+		{
+			name:    "already reported",
+			mutate:  func(live *corev1.PodSpec) { live.NodeSelector = map[string]string{"pool": "other"} },
+			drifted: []string{"spec.template.spec.nodeSelector.pool"},
+			want:    []string{"spec.template.spec.nodeSelector.pool"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := new(RocketDeploymentCreator).CreateResource(testRocket()).(*appsv1.Deployment).Spec.Template.Spec
+			live := *desired.DeepCopy()
+			tt.mutate(&live)
+			if got := podTemplateDrift(tt.drifted, &desired, &live); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("podTemplateDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
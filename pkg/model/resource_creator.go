@@ -11,6 +11,15 @@ type ResourceCreator interface {
 	Name() string
 	CreateResource(rocket *chatv1alpha1.Rocket) client.Object
 	Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey
-	// Checks if a update is needed, returns true if so
-	Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool)
+	// Update compares the desired resource from CreateResource with the current resource from the cluster.
+	// It returns the resource to apply and the paths of the drifted fields, no update is needed if no fields drifted.
+	// The returned resource only contains the fields managed by the operator, immutable and generated fields
+	// are taken over from the current resource.
+	Update(desired, cur client.Object) (client.Object, []string)
+	// DependsOn returns the creators whose resources have to exist before this resource is created or updated.
+	// Creators are identified by their Name.
+	DependsOn() []ResourceCreator
+	// IsWanted returns false if the rocket doesn't need the resource, e.g. the ingress of a rocket without host.
+	// An existing resource that isn't wanted is deleted.
+	IsWanted(rocket *chatv1alpha1.Rocket) bool
 }
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+)
+
+func TestIsWanted(t *testing.T) {
+	tests := []struct {
+		name    string
+		creator ResourceCreator
+		mutate  func(r *v1alpha1.Rocket)
+		want    bool
+	}{
+		{name: "ingress without host", creator: new(RocketIngressCreator)},
+		{name: "ingress with host", creator: new(RocketIngressCreator), mutate: func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Host = "chat.example.com" }, want: true},
+		{name: "ingress replaced by route", creator: &RocketIngressCreator{ReplacedByRoute: true}, mutate: func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Host = "chat.example.com" }},
+		{name: "ingress of a load balancer", creator: new(RocketIngressCreator), mutate: func(r *v1alpha1.Rocket) {
+			r.Spec.IngressSpec.Host = "chat.example.com"
+			r.Spec.Exposure = &v1alpha1.RocketExposure{Type: v1alpha1.ExposureLoadBalancer}
+		}},
+		{name: "route without host", creator: new(RouteCreator), want: true},
+		{name: "httproute of an ingress", creator: new(RocketHTTPRouteCreator)},
+		{name: "certificate without issuer", creator: new(RocketCertificateCreator), mutate: func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Host = "chat.example.com" }},
+		{name: "backups not scheduled", creator: new(MongodbBackupCronJobCreator)},
+		{name: "backups scheduled", creator: new(MongodbBackupCronJobCreator), mutate: func(r *v1alpha1.Rocket) {
+			r.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *"}
+		}, want: true},
+		{name: "deployment", creator: new(RocketDeploymentCreator), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			if tt.mutate != nil {
+				tt.mutate(rocket)
+			}
+			if got := tt.creator.IsWanted(rocket); got != tt.want {
+				t.Errorf("IsWanted() = %v, want %v", got, tt.want)
+			}
+			// the resource is read from the cluster to be deleted, even if the rocket doesn't need it
+			if obj := tt.creator.CreateResource(rocket); obj == nil {
+				t.Errorf("CreateResource() = nil")
+			}
+		})
+	}
+}
@@ -15,9 +15,15 @@ func (c *RocketAdminSecretCreator) Name() string {
 	return "Rocket Admin Secret"
 }
 
-func (c *RocketAdminSecretCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	// dont update secret
-	return cur, false
+func (c *RocketAdminSecretCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// never update the generated admin password
+	drifted := DriftedFields(desired, cur, "data")
+	if len(drifted) == 0 {
+		return cur, nil
+	}
+	secret := desired.(*corev1.Secret)
+	secret.Data = cur.(*corev1.Secret).Data
+	return secret, drifted
 }
 
 func (c *RocketAdminSecretCreator) CreateResource(r *chatv1alpha1.Rocket) client.Object {
@@ -39,3 +45,13 @@ func (c *RocketAdminSecretCreator) Selector(r *chatv1alpha1.Rocket) client.Objec
 		Namespace: r.Namespace,
 	}
 }
+
+// DependsOn returns the creators of the resources the RocketAdminSecretCreator depends on
+func (c *RocketAdminSecretCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, every rocket has an admin
+func (c *RocketAdminSecretCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
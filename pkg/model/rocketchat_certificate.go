@@ -0,0 +1,69 @@
+package model
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertificateGVK is the cert-manager Certificate, it is handled as unstructured object
+// so the operator runs in clusters without cert-manager
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+type RocketCertificateCreator struct{}
+
+// Name returns the ressource action of the RocketCertificateCreator
+func (c *RocketCertificateCreator) Name() string {
+	return "Rocket Certificate"
+}
+
+func (c *RocketCertificateCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	issuer := chatv1alpha1.CertificateIssuerReference{}
+	if tls := rocket.Spec.IngressSpec.TLS; tls != nil && tls.IssuerRef != nil {
+		issuer = *tls.IssuerRef
+	}
+	issuerRef := map[string]interface{}{
+		"name": issuer.Name,
+		"kind": "Issuer",
+	}
+	if issuer.Kind != "" {
+		issuerRef["kind"] = issuer.Kind
+	}
+	if issuer.Group != "" {
+		issuerRef["group"] = issuer.Group
+	}
+
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetName(rocket.Name)
+	cert.SetNamespace(rocket.Namespace)
+	cert.SetLabels(rocket.Labels)
+	cert.Object["spec"] = map[string]interface{}{
+		"secretName": IngressTLSSecretName(rocket),
+		"dnsNames":   []interface{}{rocket.Spec.IngressSpec.Host},
+		"issuerRef":  issuerRef,
+	}
+	return cert
+}
+
+func (c *RocketCertificateCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *RocketCertificateCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the RocketCertificateCreator depends on
+func (c *RocketCertificateCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true if the certificate of the ingress or route is requested from a cert-manager issuer
+func (c *RocketCertificateCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return rocket.ExposureType() == chatv1alpha1.ExposureIngress && rocket.Spec.IngressSpec.Host != "" && IsCertificateIssued(rocket)
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRocketCertificateCreator(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	rocket.Spec.IngressSpec.TLS = &chatv1alpha1.RocketIngressTLS{
+		Mode:       chatv1alpha1.IngressTLSIssuer,
+		SecretName: "chat-cert",
+		IssuerRef:  &chatv1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+	}
+	creator := new(RocketCertificateCreator)
+	cert := creator.CreateResource(rocket).(*unstructured.Unstructured)
+
+	if cert.GroupVersionKind() != CertificateGVK {
+		t.Errorf("CreateResource() gvk = %v, want %v", cert.GroupVersionKind(), CertificateGVK)
+	}
+	if secret, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName"); secret != "chat-cert" {
+		t.Errorf("CreateResource() secretName = %v", secret)
+	}
+	if names, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames"); len(names) != 1 || names[0] != "chat.example.com" {
+		t.Errorf("CreateResource() dnsNames = %v", names)
+	}
+	if kind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind"); kind != "ClusterIssuer" {
+		t.Errorf("CreateResource() issuerRef.kind = %v", kind)
+	}
+
+	// the live certificate has a status and defaulted fields
+	live := creator.CreateResource(rocket).(*unstructured.Unstructured)
+	live.Object["status"] = map[string]interface{}{"notAfter": "2030-01-01T00:00:00Z"}
+	if _, drifted := creator.Update(creator.CreateResource(rocket), live); len(drifted) != 0 {
+		t.Errorf("Update() drifted = %v, want none", drifted)
+	}
+	rocket.Spec.IngressSpec.TLS.IssuerRef.Name = "staging"
+	if _, drifted := creator.Update(creator.CreateResource(rocket), live); len(drifted) != 1 || drifted[0] != "spec.issuerRef.name" {
+		t.Errorf("Update() drifted = %v, want spec.issuerRef.name", drifted)
+	}
+}
@@ -1,9 +1,6 @@
 package model
 
 import (
-	"fmt"
-	"reflect"
-
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
@@ -20,37 +17,17 @@ type RocketDeploymentCreator struct{}
 func (c *RocketDeploymentCreator) Name() string {
 	return "Rocket Deployment"
 }
-func (c *RocketDeploymentCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-	dep := cur.(*appsv1.Deployment)
-
-	// check labels
-	if !reflect.DeepEqual(dep.Labels, rocket.Labels) {
-		dep.Labels = rocket.Labels
-		update = true
-	}
-
-	// check replicas
-	curReplicas := dep.Spec.Replicas
-	if *curReplicas != rocket.Spec.Replicas && rocket.Spec.Replicas > 0 {
-		dep.Spec.Replicas = &rocket.Spec.Replicas
-		update = true
-	}
-
-	// check image
-	curImage := dep.Spec.Template.Spec.Containers[0].Image
-	newImage := "rocketchat/rocket.chat:" + rocket.Spec.Version
-	if curImage != newImage {
-		dep.Spec.Template.Spec.Containers[0].Image = newImage
-		update = true
-	}
-
-	return dep, update
+func (c *RocketDeploymentCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// the selector of a deployment is immutable
+	drifted := DriftedFields(desired, cur, "spec.selector")
+	dep, curDep := desired.(*appsv1.Deployment), cur.(*appsv1.Deployment)
+	drifted = podTemplateDrift(drifted, &dep.Spec.Template.Spec, &curDep.Spec.Template.Spec)
+	dep.Spec.Selector = curDep.Spec.Selector
+	return dep, drifted
 }
 
 func (c *RocketDeploymentCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
 	labels := util.MergeLabels(rocketDeploymentLabels(rocket), rocket.Labels)
-	replicas := rocket.Spec.Replicas
 
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -59,6 +36,7 @@ func (c *RocketDeploymentCreator) CreateResource(rocket *chatv1alpha1.Rocket) cl
 			Labels:    rocket.Labels,
 		},
 		Spec: appsv1.DeploymentSpec{
+			ProgressDeadlineSeconds: rocket.Spec.UpgradeStrategy.ProgressDeadlineSeconds,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
@@ -74,7 +52,7 @@ func (c *RocketDeploymentCreator) CreateResource(rocket *chatv1alpha1.Rocket) cl
 					},
 					ServiceAccountName: rocket.Name,
 					Containers: []corev1.Container{{
-						Image: fmt.Sprintf("rocket.chat:%v", rocket.Spec.Version),
+						Image: RocketWebserverImage + ":" + RocketWebserverVersion(rocket),
 						Name:  "rocket",
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: 3000,
@@ -88,7 +66,8 @@ func (c *RocketDeploymentCreator) CreateResource(rocket *chatv1alpha1.Rocket) cl
 								corev1.ResourceMemory: *resource.NewQuantity(1000*1024*1024, resource.BinarySI),
 							},
 						},
-						Env: rocketDeploymentEnvVars(rocket),
+						Env:     rocketDeploymentEnvVars(rocket),
+						EnvFrom: settingsEnvFrom(rocket),
 						LivenessProbe: &corev1.Probe{
 							Handler:             corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/api/info", Port: intstr.FromString("http")}},
 							InitialDelaySeconds: 45,
@@ -99,20 +78,27 @@ func (c *RocketDeploymentCreator) CreateResource(rocket *chatv1alpha1.Rocket) cl
 						},
 						VolumeMounts: []corev1.VolumeMount{{
 							Name:      "rocket-data",
-							MountPath: "/app/uploads",
+							MountPath: RocketUploadsPath,
 						}},
 					}},
-					Volumes: []corev1.Volume{{
-						Name:         "rocket-data",
-						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
-					}},
+					Volumes: []corev1.Volume{uploadsVolume(rocket)},
 				},
 			},
 		},
 	}
-	if replicas > 0 {
-		dep.Spec.Replicas = &replicas
+	if replicas := rocket.Spec.Replicas; replicas != nil {
+		dep.Spec.Replicas = util.CreatePointerInt32(*replicas)
+	}
+	// the pods only read the sources of the settings when they start
+	if hash := rocket.Status.SettingsHash; hash != "" {
+		dep.Spec.Template.Annotations = map[string]string{SettingsHashAnnotation: hash}
 	}
+	if RouteReencrypt(rocket) {
+		pod := &dep.Spec.Template.Spec
+		pod.Containers = append(pod.Containers, routeTLSProxyContainer())
+		pod.Volumes = append(pod.Volumes, routeServingCertVolume(rocket))
+	}
+	applyPodTemplateOverrides(&dep.Spec.Template.Spec, rocket.Spec.PodTemplate)
 
 	return dep
 }
@@ -124,11 +110,15 @@ func rocketDeploymentLabels(rocket *chatv1alpha1.Rocket) map[string]string {
 }
 
 func rocketDeploymentEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
-	authSecretCreator := new(MongodbAuthSecretCreator)
+	return append(managedEnvVars(rocket), settingsEnvVars(rocket)...)
+}
+
+// managedEnvVars returns the environment variables of the webserver which are derived from the spec
+func managedEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
 	adminSecretCreator := new(RocketAdminSecretCreator)
-	authSecretReference := corev1.LocalObjectReference{Name: authSecretCreator.Selector(rocket).Name}
+	authSecretReference := DatabaseSecretReference(rocket)
 	adminSecretReference := corev1.LocalObjectReference{Name: adminSecretCreator.Selector(rocket).Name}
-	return []corev1.EnvVar{
+	env := []corev1.EnvVar{
 		{
 			Name: "MONGO_OPLOG_URL",
 			ValueFrom: &corev1.EnvVarSource{
@@ -161,7 +151,7 @@ func rocketDeploymentEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
 			Value: rocket.Spec.AdminSpec.Email,
 		},
 		{
-			Name: "ADMIN_EMAIL",
+			Name: "ADMIN_PASS",
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
 					LocalObjectReference: adminSecretReference,
@@ -178,6 +168,11 @@ func rocketDeploymentEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
 			},
 		},
 	}
+	// links in mails and the callbacks of OAuth providers are generated from the ROOT_URL
+	if url := RocketExternalURL(rocket); url != "" {
+		env = append(env, corev1.EnvVar{Name: "ROOT_URL", Value: url})
+	}
+	return append(env, uploadsEnvVars(rocket)...)
 }
 
 func (c *RocketDeploymentCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
@@ -186,3 +181,18 @@ func (c *RocketDeploymentCreator) Selector(rocket *chatv1alpha1.Rocket) client.O
 		Namespace: rocket.Namespace,
 	}
 }
+
+// DependsOn returns the creators of the resources the RocketDeploymentCreator depends on
+func (c *RocketDeploymentCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{
+		new(ServiceAccountCreator),
+		new(MongodbAuthSecretCreator),
+		new(RocketAdminSecretCreator),
+		&MongodbServiceCreator{Headless: false},
+	}
+}
+
+// IsWanted returns true, every rocket has a webserver
+func (c *RocketDeploymentCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// the env of a container is a list map keyed by name, server-side apply rejects duplicate names
+func TestRocketDeploymentEnvNamesUnique(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	rocket.Spec.Uploads = &chatv1alpha1.RocketUploads{Type: chatv1alpha1.UploadStorageGridFS}
+	rocket.Spec.Settings = map[string]string{"Site_Name": "Chat"}
+	dep := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment)
+
+	names := map[string]bool{}
+	for _, env := range dep.Spec.Template.Spec.Containers[0].Env {
+		if names[env.Name] {
+			t.Errorf("CreateResource() env %v is set twice", env.Name)
+		}
+		names[env.Name] = true
+	}
+	for _, name := range []string{"ADMIN_USERNAME", "ADMIN_EMAIL", "ADMIN_PASS"} {
+		if !names[name] {
+			t.Errorf("CreateResource() env %v is missing", name)
+		}
+	}
+}
+
+func TestRocketDeploymentAdminPassword(t *testing.T) {
+	rocket := testRocket()
+	dep := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment)
+	for _, env := range dep.Spec.Template.Spec.Containers[0].Env {
+		if env.Name != "ADMIN_PASS" {
+			continue
+		}
+		want := corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: new(RocketAdminSecretCreator).Selector(rocket).Name},
+			Key:                  "admin-password",
+		}
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil || *env.ValueFrom.SecretKeyRef != want {
+			t.Errorf("CreateResource() ADMIN_PASS = %v, want %v", env.ValueFrom, want)
+		}
+		return
+	}
+	t.Errorf("CreateResource() env ADMIN_PASS is missing")
+}
+
+func TestRocketDeploymentReplicas(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Replicas = util.CreatePointerInt32(0)
+	dep := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment)
+	if dep.Spec.Replicas == nil || *dep.Spec.Replicas != 0 {
+		t.Errorf("CreateResource() replicas = %v, want the webserver scaled down", dep.Spec.Replicas)
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HTTPRouteGVK is the HTTPRoute of the Gateway API, it is handled as unstructured object
+// like the cert-manager Certificate, the Gateway API isn't installed in every cluster
+var HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// RocketHTTPRouteCreator creates the HTTPRoute attaching Rocket.Chat to a gateway
+type RocketHTTPRouteCreator struct{}
+
+// Name returns the ressource action of the RocketHTTPRouteCreator
+func (c *RocketHTTPRouteCreator) Name() string {
+	return "Rocket HTTPRoute"
+}
+
+func (c *RocketHTTPRouteCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	gateway := chatv1alpha1.GatewayReference{}
+	if rocket.Spec.Exposure != nil && rocket.Spec.Exposure.Gateway != nil {
+		gateway = *rocket.Spec.Exposure.Gateway
+	}
+	parentRef := map[string]interface{}{
+		"name": gateway.Name,
+	}
+	if gateway.Namespace != "" {
+		parentRef["namespace"] = gateway.Namespace
+	}
+	if gateway.SectionName != "" {
+		parentRef["sectionName"] = gateway.SectionName
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": ingressPath(rocket),
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": rocket.Name + RocketWebserverServiceSuffix,
+						"port": int64(RocketServicePort),
+					},
+				},
+			},
+		},
+	}
+	if host := rocket.Spec.IngressSpec.Host; host != "" {
+		spec["hostnames"] = []interface{}{host}
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(rocket.Name)
+	route.SetNamespace(rocket.Namespace)
+	route.SetLabels(rocket.Labels)
+	route.Object["spec"] = spec
+	return route
+}
+
+func (c *RocketHTTPRouteCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *RocketHTTPRouteCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the RocketHTTPRouteCreator depends on
+func (c *RocketHTTPRouteCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{new(RocketServiceCreator)}
+}
+
+// IsWanted returns true if Rocket.Chat is exposed by a gateway
+func (c *RocketHTTPRouteCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return rocket.ExposureType() == chatv1alpha1.ExposureGatewayHTTPRoute
+}
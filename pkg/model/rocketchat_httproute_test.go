@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRocketHTTPRouteCreator(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	rocket.Spec.IngressSpec.Path = "/chat"
+	rocket.Spec.Exposure = &chatv1alpha1.RocketExposure{
+		Type:    chatv1alpha1.ExposureGatewayHTTPRoute,
+		Gateway: &chatv1alpha1.GatewayReference{Name: "public", Namespace: "gateways"},
+	}
+	creator := new(RocketHTTPRouteCreator)
+	route := creator.CreateResource(rocket).(*unstructured.Unstructured)
+
+	if route.GroupVersionKind() != HTTPRouteGVK {
+		t.Errorf("CreateResource() gvk = %v, want %v", route.GroupVersionKind(), HTTPRouteGVK)
+	}
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parents) != 1 || parents[0].(map[string]interface{})["namespace"] != "gateways" {
+		t.Errorf("CreateResource() parentRefs = %v", parents)
+	}
+	if hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames"); len(hostnames) != 1 || hostnames[0] != "chat.example.com" {
+		t.Errorf("CreateResource() hostnames = %v", hostnames)
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	rule := rules[0].(map[string]interface{})
+	match := rule["matches"].([]interface{})[0].(map[string]interface{})
+	if value, _, _ := unstructured.NestedString(match, "path", "value"); value != "/chat" {
+		t.Errorf("CreateResource() path = %v, want /chat", value)
+	}
+	backend := rule["backendRefs"].([]interface{})[0].(map[string]interface{})
+	if backend["name"] != "test"+RocketWebserverServiceSuffix {
+		t.Errorf("CreateResource() backend = %v", backend)
+	}
+
+	// fields defaulted by the api server aren't drift
+	live := creator.CreateResource(rocket).(*unstructured.Unstructured)
+	liveParents, _, _ := unstructured.NestedSlice(live.Object, "spec", "parentRefs")
+	liveParents[0].(map[string]interface{})["kind"] = "Gateway"
+	if err := unstructured.SetNestedSlice(live.Object, liveParents, "spec", "parentRefs"); err != nil {
+		t.Fatal(err)
+	}
+	if _, drifted := creator.Update(creator.CreateResource(rocket), live); len(drifted) != 0 {
+		t.Errorf("Update() drifted = %v, want none", drifted)
+	}
+}
+
+func TestRocketServiceType(t *testing.T) {
+	tests := []struct {
+		exposure chatv1alpha1.ExposureType
+		want     corev1.ServiceType
+		wantURL  string
+	}{
+		{exposure: "", want: corev1.ServiceTypeClusterIP, wantURL: "https://chat.example.com"},
+		{exposure: chatv1alpha1.ExposureGatewayHTTPRoute, want: corev1.ServiceTypeClusterIP, wantURL: "https://chat.example.com"},
+		{exposure: chatv1alpha1.ExposureLoadBalancer, want: corev1.ServiceTypeLoadBalancer, wantURL: "http://chat.example.com"},
+		{exposure: chatv1alpha1.ExposureNodePort, want: corev1.ServiceTypeNodePort},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.exposure), func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.IngressSpec.Host = "chat.example.com"
+			if tt.exposure != "" {
+				rocket.Spec.Exposure = &chatv1alpha1.RocketExposure{Type: tt.exposure}
+			}
+			service := new(RocketServiceCreator).CreateResource(rocket).(*corev1.Service)
+			if service.Spec.Type != tt.want {
+				t.Errorf("CreateResource() type = %v, want %v", service.Spec.Type, tt.want)
+			}
+			if got := RocketExternalURL(rocket); got != tt.wantURL {
+				t.Errorf("RocketExternalURL() = %v, want %v", got, tt.wantURL)
+			}
+		})
+	}
+}
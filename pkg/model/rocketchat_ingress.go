@@ -1,7 +1,7 @@
 package model
 
 import (
-	"reflect"
+	"path"
 
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -9,30 +9,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type RocketIngressCreator struct{}
+type RocketIngressCreator struct {
+	// ReplacedByRoute is true on OpenShift, where the route exposes Rocket.Chat instead of the ingress
+	ReplacedByRoute bool
+}
 
 // Name returns the ressource action of the RocketIngressCreator
 func (c *RocketIngressCreator) Name() string {
 	return "Rocket Ingress"
 }
-func (c *RocketIngressCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-
-	ingress := cur.(*networkingv1.Ingress)
-	// check labels
-	if !reflect.DeepEqual(ingress.Labels, rocket.Labels) {
-		ingress.Labels = rocket.Labels
-		update = true
-	}
-
-	return ingress, update
+func (c *RocketIngressCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
 }
 
 func (c *RocketIngressCreator) CreateResource(r *chatv1alpha1.Rocket) client.Object {
 	serviceSelector := new(RocketServiceCreator).Selector(r)
 	ingressPathType := networkingv1.PathTypeImplementationSpecific
 
-	return &networkingv1.Ingress{
+	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        r.Name,
 			Namespace:   r.Namespace,
@@ -40,14 +34,6 @@ func (c *RocketIngressCreator) CreateResource(r *chatv1alpha1.Rocket) client.Obj
 			Annotations: r.Spec.IngressSpec.Annotations,
 		},
 		Spec: networkingv1.IngressSpec{
-			TLS: []networkingv1.IngressTLS{
-				{
-					Hosts: []string{
-						r.Spec.IngressSpec.Host,
-					},
-					SecretName: r.Name + "-tls",
-				},
-			},
 			Rules: []networkingv1.IngressRule{
 				{
 					Host: r.Spec.IngressSpec.Host,
@@ -55,7 +41,7 @@ func (c *RocketIngressCreator) CreateResource(r *chatv1alpha1.Rocket) client.Obj
 						HTTP: &networkingv1.HTTPIngressRuleValue{
 							Paths: []networkingv1.HTTPIngressPath{
 								{
-									Path:     "/",
+									Path:     ingressPath(r),
 									PathType: &ingressPathType,
 									Backend: networkingv1.IngressBackend{
 										Service: &networkingv1.IngressServiceBackend{
@@ -73,6 +59,17 @@ func (c *RocketIngressCreator) CreateResource(r *chatv1alpha1.Rocket) client.Obj
 			},
 		},
 	}
+	if IngressTLSEnabled(r) {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts: []string{
+					r.Spec.IngressSpec.Host,
+				},
+				SecretName: IngressTLSSecretName(r),
+			},
+		}
+	}
+	return ingress
 }
 
 func (c *RocketIngressCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKey {
@@ -81,3 +78,58 @@ func (c *RocketIngressCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKey
 		Namespace: r.Namespace,
 	}
 }
+
+// DependsOn returns the creators of the resources the RocketIngressCreator depends on
+func (c *RocketIngressCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{new(RocketServiceCreator)}
+}
+
+// IsWanted returns true if Rocket.Chat is exposed by an ingress with a host and no route replaces it
+func (c *RocketIngressCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return !c.ReplacedByRoute && rocket.ExposureType() == chatv1alpha1.ExposureIngress && rocket.Spec.IngressSpec.Host != ""
+}
+
+// RocketExternalURL returns the URL Rocket.Chat is reached at from outside the cluster, empty if the ingress has no host.
+// The port of a NodePort exposure is allocated by the cluster, so its URL isn't known either.
+func RocketExternalURL(rocket *chatv1alpha1.Rocket) string {
+	host := rocket.Spec.IngressSpec.Host
+	exposure := rocket.ExposureType()
+	if host == "" || exposure == chatv1alpha1.ExposureNodePort {
+		return ""
+	}
+	scheme := "http"
+	// the service of a load balancer exposure serves plain http
+	if exposure != chatv1alpha1.ExposureLoadBalancer && IngressTLSEnabled(rocket) {
+		scheme = "https"
+	}
+	url := scheme + "://" + host
+	if path := ingressPath(rocket); path != "/" {
+		url += path
+	}
+	return url
+}
+
+// IngressTLSEnabled returns true if the ingress terminates TLS
+func IngressTLSEnabled(rocket *chatv1alpha1.Rocket) bool {
+	tls := rocket.Spec.IngressSpec.TLS
+	return tls == nil || tls.Mode != chatv1alpha1.IngressTLSNone
+}
+
+// IngressTLSSecretName returns the secret containing the certificate of the ingress
+func IngressTLSSecretName(rocket *chatv1alpha1.Rocket) string {
+	if tls := rocket.Spec.IngressSpec.TLS; tls != nil && tls.SecretName != "" {
+		return tls.SecretName
+	}
+	return rocket.Name + RocketTLSSecretSuffix
+}
+
+// IsCertificateIssued returns true if the certificate of the ingress is requested from a cert-manager issuer
+func IsCertificateIssued(rocket *chatv1alpha1.Rocket) bool {
+	tls := rocket.Spec.IngressSpec.TLS
+	return tls != nil && tls.Mode == chatv1alpha1.IngressTLSIssuer
+}
+
+// ingressPath returns the cleaned path prefix of the ingress without a trailing slash, / if it is unset
+func ingressPath(rocket *chatv1alpha1.Rocket) string {
+	return path.Clean("/" + rocket.Spec.IngressSpec.Path)
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestRocketExternalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		path string
+		tls  *chatv1alpha1.RocketIngressTLS
+		want string
+	}{
+		{name: "no host", path: "/chat"},
+		{name: "root", host: "chat.example.com", want: "https://chat.example.com"},
+		{name: "path prefix", host: "chat.example.com", path: "/chat/", want: "https://chat.example.com/chat"},
+		{name: "without tls", host: "chat.example.com", tls: &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSNone}, want: "http://chat.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.IngressSpec.Host = tt.host
+			rocket.Spec.IngressSpec.Path = tt.path
+			rocket.Spec.IngressSpec.TLS = tt.tls
+			if got := RocketExternalURL(rocket); got != tt.want {
+				t.Errorf("RocketExternalURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootURL(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	rocket.Spec.IngressSpec.Path = "/chat"
+
+	var rootURL string
+	for _, env := range new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "ROOT_URL" {
+			rootURL = env.Value
+		}
+	}
+	if rootURL != "https://chat.example.com/chat" {
+		t.Errorf("CreateResource() ROOT_URL = %q", rootURL)
+	}
+	ingress := new(RocketIngressCreator).CreateResource(rocket).(*networkingv1.Ingress)
+	if path := ingress.Spec.Rules[0].HTTP.Paths[0].Path; path != "/chat" {
+		t.Errorf("CreateResource() ingress path = %v", path)
+	}
+}
+
+func TestRocketIngressTLS(t *testing.T) {
+	tests := []struct {
+		name       string
+		tls        *chatv1alpha1.RocketIngressTLS
+		wantSecret string
+	}{
+		{name: "default", wantSecret: "test-tls"},
+		{name: "none", tls: &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSNone}},
+		{name: "existing secret", tls: &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSSecret, SecretName: "chat-cert"}, wantSecret: "chat-cert"},
+		{name: "issuer", tls: &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSIssuer}, wantSecret: "test-tls"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.IngressSpec.Host = "chat.example.com"
+			rocket.Spec.IngressSpec.TLS = tt.tls
+			ingress := new(RocketIngressCreator).CreateResource(rocket).(*networkingv1.Ingress)
+			if tt.wantSecret == "" {
+				if len(ingress.Spec.TLS) != 0 {
+					t.Errorf("CreateResource() tls = %v, want none", ingress.Spec.TLS)
+				}
+				return
+			}
+			if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != tt.wantSecret {
+				t.Errorf("CreateResource() tls = %v, want secret %v", ingress.Spec.TLS, tt.wantSecret)
+			}
+		})
+	}
+}
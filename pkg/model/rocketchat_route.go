@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteGVK is the OpenShift Route, it is handled as unstructured object and only used on OpenShift
+var RouteGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// RouteCreator creates the Route exposing Rocket.Chat on OpenShift instead of an ingress.
+// Without a host OpenShift generates one, without a certificate the default certificate of the router is used.
+type RouteCreator struct{}
+
+// Name returns the ressource action of the RouteCreator
+func (c *RouteCreator) Name() string {
+	return "Rocket Route"
+}
+
+func (c *RouteCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   rocket.Name + RocketWebserverServiceSuffix,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": routeTargetPort(rocket),
+		},
+	}
+	if host := rocket.Spec.IngressSpec.Host; host != "" {
+		spec["host"] = host
+	}
+	if path := ingressPath(rocket); path != "/" {
+		spec["path"] = path
+	}
+	if tls := routeTLS(rocket); tls != nil {
+		spec["tls"] = tls
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(RouteGVK)
+	route.SetName(rocket.Name)
+	route.SetNamespace(rocket.Namespace)
+	route.SetLabels(rocket.Labels)
+	route.Object["spec"] = spec
+	return route
+}
+
+// routeTLS returns the TLS configuration of the route, nil if TLS is disabled.
+// TLS is terminated by the router, reencrypting routes open a new TLS connection to the TLS proxy of the webserver pods.
+// Without a destination CA certificate the router verifies the proxy with the service CA.
+func routeTLS(rocket *chatv1alpha1.Rocket) map[string]interface{} {
+	if !IngressTLSEnabled(rocket) {
+		return nil
+	}
+	tls := map[string]interface{}{
+		"termination":                   "edge",
+		"insecureEdgeTerminationPolicy": "Redirect",
+	}
+	if RouteReencrypt(rocket) {
+		tls["termination"] = "reencrypt"
+	}
+	if routeExternalCertificate(rocket) {
+		tls["externalCertificate"] = map[string]interface{}{
+			"name": IngressTLSSecretName(rocket),
+		}
+	}
+	return tls
+}
+
+// routeExternalCertificate returns true if the route references the certificate of a secret.
+// The secret is only referenced if one is configured, since the route isn't admitted if the secret is missing.
+// Routes with external certificates require OpenShift 4.16 or newer.
+func routeExternalCertificate(rocket *chatv1alpha1.Rocket) bool {
+	spec := rocket.Spec.IngressSpec.TLS
+	return IngressTLSEnabled(rocket) && spec != nil && (spec.Mode == chatv1alpha1.IngressTLSIssuer || spec.SecretName != "")
+}
+
+// RouteReencrypt returns true if the route reencrypts the traffic to the webserver
+func RouteReencrypt(rocket *chatv1alpha1.Rocket) bool {
+	tls := rocket.Spec.IngressSpec.TLS
+	return rocket.ExposureType() == chatv1alpha1.ExposureIngress && IngressTLSEnabled(rocket) &&
+		tls != nil && tls.Termination == chatv1alpha1.RouteTerminationReencrypt
+}
+
+// routeTargetPort returns the port of the webserver service the route sends the traffic to
+func routeTargetPort(rocket *chatv1alpha1.Rocket) string {
+	if RouteReencrypt(rocket) {
+		return "https"
+	}
+	return "http"
+}
+
+// routeServingCertName returns the secret the OpenShift service CA stores the certificate of the TLS proxy in
+func routeServingCertName(rocket *chatv1alpha1.Rocket) string {
+	return rocket.Name + RouteServingCertSuffix
+}
+
+// routeTLSProxyContainer returns the sidecar of the webserver terminating the reencrypted traffic of the route,
+// Rocket.Chat itself only serves plain http.
+func routeTLSProxyContainer() corev1.Container {
+	return corev1.Container{
+		Name:  "tls-proxy",
+		Image: RouteTLSProxyImage,
+		Args: []string{
+			"server",
+			fmt.Sprintf("--listen=0.0.0.0:%d", RouteTLSProxyPort),
+			"--target=127.0.0.1:3000",
+			"--cert=" + RouteTLSProxyCertPath + "/tls.crt",
+			"--key=" + RouteTLSProxyCertPath + "/tls.key",
+			// the router doesn't present a client certificate
+			"--disable-authentication",
+		},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: RouteTLSProxyPort,
+			Name:          "https",
+		}},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "serving-cert",
+			MountPath: RouteTLSProxyCertPath,
+			ReadOnly:  true,
+		}},
+	}
+}
+
+// routeServingCertVolume returns the volume of the certificate issued by the service CA for the TLS proxy
+func routeServingCertVolume(rocket *chatv1alpha1.Rocket) corev1.Volume {
+	return corev1.Volume{
+		Name: "serving-cert",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: routeServingCertName(rocket)},
+		},
+	}
+}
+
+func (c *RouteCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *RouteCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the RouteCreator depends on
+func (c *RouteCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{new(RocketServiceCreator), new(RouteCertificateRoleBindingCreator)}
+}
+
+// IsWanted returns true if Rocket.Chat is exposed by an ingress, OpenShift generates a host for routes without one
+func (c *RouteCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return rocket.ExposureType() == chatv1alpha1.ExposureIngress
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteCertificateRoleCreator creates the Role allowing the OpenShift router to read the certificate referenced by the route.
+// The router only admits routes with an external certificate it is allowed to read.
+type RouteCertificateRoleCreator struct{}
+
+// Name returns the ressource action of the RouteCertificateRoleCreator
+func (c *RouteCertificateRoleCreator) Name() string {
+	return "Route Certificate Role"
+}
+
+func (c *RouteCertificateRoleCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rocket.Name + RouteCertificateReaderSuffix,
+			Namespace: rocket.Namespace,
+			Labels:    rocket.Labels,
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{corev1.GroupName},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{IngressTLSSecretName(rocket)},
+			Verbs:         []string{"get", "list", "watch"},
+		}},
+	}
+}
+
+func (c *RouteCertificateRoleCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name + RouteCertificateReaderSuffix,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *RouteCertificateRoleCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the RouteCertificateRoleCreator depends on
+func (c *RouteCertificateRoleCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true if the route references a certificate
+func (c *RouteCertificateRoleCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return new(RouteCreator).IsWanted(rocket) && routeExternalCertificate(rocket)
+}
+
+// RouteCertificateRoleBindingCreator binds the Role reading the certificate of the route to the service account of the OpenShift router
+type RouteCertificateRoleBindingCreator struct {
+	// Router is the service account of the router admitting the routes, defaults to the default OpenShift router
+	Router types.NamespacedName
+}
+
+// Name returns the ressource action of the RouteCertificateRoleBindingCreator
+func (c *RouteCertificateRoleBindingCreator) Name() string {
+	return "Route Certificate Role Binding"
+}
+
+func (c *RouteCertificateRoleBindingCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
+	router := c.Router
+	if router.Name == "" {
+		router = types.NamespacedName{Namespace: RouterNamespace, Name: RouterServiceAccount}
+	}
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rocket.Name + RouteCertificateReaderSuffix,
+			Namespace: rocket.Namespace,
+			Labels:    rocket.Labels,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     new(RouteCertificateRoleCreator).Selector(rocket).Name,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      router.Name,
+			Namespace: router.Namespace,
+		}},
+	}
+}
+
+func (c *RouteCertificateRoleBindingCreator) Selector(rocket *chatv1alpha1.Rocket) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      rocket.Name + RouteCertificateReaderSuffix,
+		Namespace: rocket.Namespace,
+	}
+}
+
+func (c *RouteCertificateRoleBindingCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
+
+// DependsOn returns the creators of the resources the RouteCertificateRoleBindingCreator depends on
+func (c *RouteCertificateRoleBindingCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{new(RouteCertificateRoleCreator)}
+}
+
+// IsWanted returns true if the route references a certificate
+func (c *RouteCertificateRoleBindingCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return new(RouteCertificateRoleCreator).IsWanted(rocket)
+}
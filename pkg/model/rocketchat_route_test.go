@@ -0,0 +1,155 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRouteCreator(t *testing.T) {
+	tests := []struct {
+		name     string
+		tls      *chatv1alpha1.RocketIngressTLS
+		wantTLS  map[string]interface{}
+		wantPort string
+	}{
+		{
+			name:     "router certificate",
+			wantTLS:  map[string]interface{}{"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect"},
+			wantPort: "http",
+		},
+		{
+			name:     "no tls",
+			tls:      &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSNone},
+			wantPort: "http",
+		},
+		{
+			name: "reencrypt with the router certificate",
+			tls:  &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSSecret, Termination: chatv1alpha1.RouteTerminationReencrypt},
+			wantTLS: map[string]interface{}{
+				"termination":                   "reencrypt",
+				"insecureEdgeTerminationPolicy": "Redirect",
+			},
+			wantPort: "https",
+		},
+		{
+			name: "certificate from a secret",
+			tls:  &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSSecret, SecretName: "chat-cert"},
+			wantTLS: map[string]interface{}{
+				"termination":                   "edge",
+				"insecureEdgeTerminationPolicy": "Redirect",
+				"externalCertificate":           map[string]interface{}{"name": "chat-cert"},
+			},
+			wantPort: "http",
+		},
+		{
+			name: "issued certificate",
+			tls:  &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSIssuer},
+			wantTLS: map[string]interface{}{
+				"termination":                   "edge",
+				"insecureEdgeTerminationPolicy": "Redirect",
+				"externalCertificate":           map[string]interface{}{"name": "test-tls"},
+			},
+			wantPort: "http",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.IngressSpec.Host = "chat.example.com"
+			rocket.Spec.IngressSpec.TLS = tt.tls
+			rocket.Spec.IngressSpec.Annotations = map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "50m"}
+			route := new(RouteCreator).CreateResource(rocket).(*unstructured.Unstructured)
+
+			if route.GroupVersionKind() != RouteGVK {
+				t.Errorf("CreateResource() gvk = %v, want %v", route.GroupVersionKind(), RouteGVK)
+			}
+			if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "chat.example.com" {
+				t.Errorf("CreateResource() host = %v", host)
+			}
+			if annotations := route.GetAnnotations(); len(annotations) != 0 {
+				t.Errorf("CreateResource() annotations = %v, want none", annotations)
+			}
+			if service, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name"); service != "test"+RocketWebserverServiceSuffix {
+				t.Errorf("CreateResource() service = %v", service)
+			}
+			tls, _, _ := unstructured.NestedMap(route.Object, "spec", "tls")
+			if !reflect.DeepEqual(tls, tt.wantTLS) {
+				t.Errorf("CreateResource() tls = %v, want %v", tls, tt.wantTLS)
+			}
+			if port, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort"); port != tt.wantPort {
+				t.Errorf("CreateResource() target port = %v, want %v", port, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestRouteCertificateRole(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	roleCreator, bindingCreator := new(RouteCertificateRoleCreator), new(RouteCertificateRoleBindingCreator)
+	if roleCreator.IsWanted(rocket) || bindingCreator.IsWanted(rocket) {
+		t.Errorf("IsWanted() = true for the default certificate of the router")
+	}
+
+	rocket.Spec.IngressSpec.TLS = &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSSecret, SecretName: "chat-cert"}
+	if !roleCreator.IsWanted(rocket) || !bindingCreator.IsWanted(rocket) {
+		t.Errorf("IsWanted() = false for a certificate from a secret")
+	}
+	role := roleCreator.CreateResource(rocket).(*rbacv1.Role)
+	if names := role.Rules[0].ResourceNames; len(names) != 1 || names[0] != "chat-cert" {
+		t.Errorf("CreateResource() role grants %v, want chat-cert", names)
+	}
+	binding := bindingCreator.CreateResource(rocket).(*rbacv1.RoleBinding)
+	if binding.RoleRef.Name != role.Name {
+		t.Errorf("CreateResource() binding references %v, want %v", binding.RoleRef.Name, role.Name)
+	}
+	if subject := binding.Subjects[0]; subject.Name != RouterServiceAccount || subject.Namespace != RouterNamespace {
+		t.Errorf("CreateResource() binding subject = %v", subject)
+	}
+	bindingCreator.Router = types.NamespacedName{Namespace: "ingress-shard", Name: "shard-router"}
+	binding = bindingCreator.CreateResource(rocket).(*rbacv1.RoleBinding)
+	if subject := binding.Subjects[0]; subject.Name != "shard-router" || subject.Namespace != "ingress-shard" {
+		t.Errorf("CreateResource() binding subject = %v, want the configured router", subject)
+	}
+
+	rocket.Spec.Exposure = &chatv1alpha1.RocketExposure{Type: chatv1alpha1.ExposureLoadBalancer}
+	if roleCreator.IsWanted(rocket) {
+		t.Errorf("IsWanted() = true without a route")
+	}
+}
+
+func TestRouteReencrypt(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.IngressSpec.TLS = &chatv1alpha1.RocketIngressTLS{Mode: chatv1alpha1.IngressTLSSecret, Termination: chatv1alpha1.RouteTerminationReencrypt}
+
+	service := new(RocketServiceCreator).CreateResource(rocket).(*corev1.Service)
+	if name := service.Annotations[ServingCertSecretAnnotation]; name != "test"+RouteServingCertSuffix {
+		t.Errorf("CreateResource() service serving certificate = %v", name)
+	}
+	if ports := service.Spec.Ports; len(ports) != 2 || ports[1].Name != "https" {
+		t.Errorf("CreateResource() service ports = %v, want http and https", ports)
+	}
+	pod := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment).Spec.Template.Spec
+	if len(pod.Containers) != 2 || pod.Containers[0].Name != "rocket" || pod.Containers[1].Name != "tls-proxy" {
+		t.Fatalf("CreateResource() deployment containers = %v, want rocket and tls-proxy", pod.Containers)
+	}
+	if volume := pod.Volumes[len(pod.Volumes)-1]; volume.Secret == nil || volume.Secret.SecretName != "test"+RouteServingCertSuffix {
+		t.Errorf("CreateResource() deployment volume = %v, want the serving certificate", volume)
+	}
+
+	rocket.Spec.Exposure = &chatv1alpha1.RocketExposure{Type: chatv1alpha1.ExposureLoadBalancer}
+	if RouteReencrypt(rocket) {
+		t.Errorf("RouteReencrypt() = true without a route")
+	}
+	service = new(RocketServiceCreator).CreateResource(rocket).(*corev1.Service)
+	if len(service.Annotations) != 0 || len(service.Spec.Ports) != 1 {
+		t.Errorf("CreateResource() service = %v, want only the http port", service)
+	}
+}
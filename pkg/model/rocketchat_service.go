@@ -1,8 +1,6 @@
 package model
 
 import (
-	"reflect"
-
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -17,19 +15,8 @@ type RocketServiceCreator struct{}
 func (c *RocketServiceCreator) Name() string {
 	return "Rocket Service"
 }
-func (c *RocketServiceCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-
-	service := cur.(*corev1.Service)
-	// check labels
-	labels := util.MergeLabels(rocketDeploymentLabels(rocket), rocket.Labels)
-	if !reflect.DeepEqual(service.Labels, labels) {
-		service.Labels = labels
-		service.Spec.Selector = labels
-		update = true
-	}
-
-	return service, update
+func (c *RocketServiceCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	return updateService(desired, cur)
 }
 
 func (c *RocketServiceCreator) CreateResource(rocket *chatv1alpha1.Rocket) client.Object {
@@ -41,15 +28,23 @@ func (c *RocketServiceCreator) CreateResource(rocket *chatv1alpha1.Rocket) clien
 			Labels:    labels,
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
+			Type: rocketServiceType(rocket),
 			Ports: []corev1.ServicePort{{
 				TargetPort: intstr.FromString("http"),
-				Port:       80,
+				Port:       RocketServicePort,
 				Name:       "http",
 			}},
 			Selector: labels,
 		},
 	}
+	if RouteReencrypt(rocket) {
+		service.Annotations = map[string]string{ServingCertSecretAnnotation: routeServingCertName(rocket)}
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			TargetPort: intstr.FromString("https"),
+			Port:       RouteTLSProxyServicePort,
+			Name:       "https",
+		})
+	}
 	return service
 }
 
@@ -59,3 +54,32 @@ func (c *RocketServiceCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKey
 		Namespace: r.Namespace,
 	}
 }
+
+// rocketServiceType returns the type of the webserver service, it is only reachable inside the cluster
+// unless the service itself exposes Rocket.Chat
+func rocketServiceType(rocket *chatv1alpha1.Rocket) corev1.ServiceType {
+	switch rocket.ExposureType() {
+	case chatv1alpha1.ExposureLoadBalancer:
+		return corev1.ServiceTypeLoadBalancer
+	case chatv1alpha1.ExposureNodePort:
+		return corev1.ServiceTypeNodePort
+	default:
+		return corev1.ServiceTypeClusterIP
+	}
+}
+
+// DependsOn returns the creators of the resources the RocketServiceCreator depends on
+func (c *RocketServiceCreator) DependsOn() []ResourceCreator {
+	return nil
+}
+
+// IsWanted returns true, the service is the backend of every exposure
+func (c *RocketServiceCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
+
+// updateService compares the desired with the current service.
+// Fields allocated by the api server, like the clusterIP, are not part of the desired service and are kept on apply.
+func updateService(desired, cur client.Object) (client.Object, []string) {
+	return desired, DriftedFields(desired, cur)
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	// the list of known settings is embedded into the binary
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed settings.yaml
+var embeddedKnownSettings []byte
+
+var knownSettings = mustParseKnownSettings(embeddedKnownSettings)
+
+// derivedSettings maps environment variables of the webserver to the settings Rocket.Chat derives from them
+var derivedSettings = map[string]string{
+	"ROOT_URL": "Site_Url",
+}
+
+// knownSettingList lists the settings of Rocket.Chat which can be overwritten
+type knownSettingList struct {
+	Settings []string `json:"settings"`
+	// Prefixes match the settings of providers added at runtime
+	Prefixes []string `json:"prefixes"`
+}
+
+func mustParseKnownSettings(data []byte) *knownSettingList {
+	list := &knownSettingList{}
+	if err := yaml.UnmarshalStrict(data, list); err != nil {
+		panic(fmt.Sprintf("embedded list of settings is invalid: %v", err))
+	}
+	return list
+}
+
+// IsKnownSetting returns true if the setting ID is a known setting of Rocket.Chat
+func IsKnownSetting(id string) bool {
+	for _, setting := range knownSettings.Settings {
+		if setting == id {
+			return true
+		}
+	}
+	for _, prefix := range knownSettings.Prefixes {
+		if strings.HasPrefix(id, prefix) && len(id) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsManagedSetting returns true if the operator overwrites the setting itself, e.g. the storage of spec.uploads,
+// or the setting is derived from an environment variable set by the operator, e.g. the Site_Url from the ROOT_URL
+func IsManagedSetting(rocket *chatv1alpha1.Rocket, id string) bool {
+	for _, env := range managedEnvVars(rocket) {
+		if env.Name == RocketSettingEnvPrefix+id || derivedSettings[env.Name] == id {
+			return true
+		}
+	}
+	return false
+}
+
+// settingsEnvVars overwrites the settings of the spec, sorted by their ID so the pod template is stable
+func settingsEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
+	ids := make([]string, 0, len(rocket.Spec.Settings))
+	for id := range rocket.Spec.Settings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	env := make([]corev1.EnvVar, 0, len(ids))
+	for _, id := range ids {
+		env = append(env, settingEnvVar(id, rocket.Spec.Settings[id]))
+	}
+	return env
+}
+
+// settingsEnvFrom overwrites the settings with the keys of the sources, the environment variables of the spec take precedence
+func settingsEnvFrom(rocket *chatv1alpha1.Rocket) []corev1.EnvFromSource {
+	var envFrom []corev1.EnvFromSource
+	for _, source := range rocket.Spec.SettingsFrom {
+		from := corev1.EnvFromSource{Prefix: RocketSettingEnvPrefix}
+		if source.SecretRef != nil {
+			from.SecretRef = &corev1.SecretEnvSource{LocalObjectReference: *source.SecretRef}
+		}
+		if source.ConfigMapRef != nil {
+			from.ConfigMapRef = &corev1.ConfigMapEnvSource{LocalObjectReference: *source.ConfigMapRef}
+		}
+		envFrom = append(envFrom, from)
+	}
+	return envFrom
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsKnownSetting(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "Accounts_RegistrationForm", want: true},
+		{id: "Accounts_OAuth_Custom-Keycloak-id", want: true},
+		{id: "Accounts_OAuth_", want: false},
+		{id: "accounts_registrationform", want: false},
+		{id: "Not_A_Setting", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := IsKnownSetting(tt.id); got != tt.want {
+				t.Errorf("IsKnownSetting() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsManagedSetting(t *testing.T) {
+	rocket := testRocket()
+	if !IsManagedSetting(rocket, "Show_Setup_Wizard") {
+		t.Errorf("IsManagedSetting(Show_Setup_Wizard) = false, want true")
+	}
+	if IsManagedSetting(rocket, "FileUpload_Storage_Type") {
+		t.Errorf("IsManagedSetting(FileUpload_Storage_Type) = true without uploads, want false")
+	}
+	rocket.Spec.Uploads = &chatv1alpha1.RocketUploads{Type: chatv1alpha1.UploadStorageGridFS}
+	if !IsManagedSetting(rocket, "FileUpload_Storage_Type") {
+		t.Errorf("IsManagedSetting(FileUpload_Storage_Type) = false with uploads, want true")
+	}
+	rocket.Spec.IngressSpec.Host = ""
+	if RocketExternalURL(rocket) != "" || IsManagedSetting(rocket, "Site_Url") {
+		t.Errorf("IsManagedSetting(Site_Url) = true without a ROOT_URL, want false")
+	}
+	rocket.Spec.IngressSpec.Host = "chat.example.com"
+	if !IsManagedSetting(rocket, "Site_Url") {
+		t.Errorf("IsManagedSetting(Site_Url) = false with the ROOT_URL %v, want true", RocketExternalURL(rocket))
+	}
+}
+
+func TestRocketSettings(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Settings = map[string]string{"Site_Name": "Chat", "Accounts_RegistrationForm": "Disabled"}
+	rocket.Spec.SettingsFrom = []chatv1alpha1.SettingsSource{
+		{SecretRef: &corev1.LocalObjectReference{Name: "oauth"}},
+		{ConfigMapRef: &corev1.LocalObjectReference{Name: "settings"}},
+	}
+	rocket.Status.SettingsHash = "abc"
+	dep := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment)
+	container := dep.Spec.Template.Spec.Containers[0]
+
+	var settings []string
+	for _, env := range container.Env {
+		if env.Name == RocketSettingEnvPrefix+"Accounts_RegistrationForm" || env.Name == RocketSettingEnvPrefix+"Site_Name" {
+			settings = append(settings, env.Name)
+		}
+	}
+	want := []string{RocketSettingEnvPrefix + "Accounts_RegistrationForm", RocketSettingEnvPrefix + "Site_Name"}
+	if len(settings) != 2 || settings[0] != want[0] || settings[1] != want[1] {
+		t.Errorf("CreateResource() settings = %v, want %v", settings, want)
+	}
+
+	if len(container.EnvFrom) != 2 {
+		t.Fatalf("CreateResource() envFrom = %v, want 2 sources", container.EnvFrom)
+	}
+	if from := container.EnvFrom[0]; from.Prefix != RocketSettingEnvPrefix || from.SecretRef == nil || from.SecretRef.Name != "oauth" {
+		t.Errorf("CreateResource() envFrom[0] = %v", from)
+	}
+	if from := container.EnvFrom[1]; from.Prefix != RocketSettingEnvPrefix || from.ConfigMapRef == nil || from.ConfigMapRef.Name != "settings" {
+		t.Errorf("CreateResource() envFrom[1] = %v", from)
+	}
+	if hash := dep.Spec.Template.Annotations[SettingsHashAnnotation]; hash != "abc" {
+		t.Errorf("CreateResource() settings hash = %v, want abc", hash)
+	}
+
+	// a changed source rolls the deployment
+	live := liveDeployment(rocket)
+	rocket.Status.SettingsHash = "def"
+	if _, drifted := new(RocketDeploymentCreator).Update(new(RocketDeploymentCreator).CreateResource(rocket), live); len(drifted) == 0 {
+		t.Errorf("Update() drifted = none, want the settings hash")
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strconv"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// uploadsVolume returns the volume mounted at the upload path of Rocket.Chat.
+// Files are only written to it by the FileSystem storage, which shares a claim between all pods.
+func uploadsVolume(rocket *chatv1alpha1.Rocket) corev1.Volume {
+	volume := corev1.Volume{
+		Name:         "rocket-data",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+	if uploads := rocket.Spec.Uploads; uploads != nil && uploads.Type == chatv1alpha1.UploadStorageFileSystem {
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: uploads.ClaimName},
+		}
+	}
+	return volume
+}
+
+// uploadsEnvVars overwrites the file upload settings of Rocket.Chat with the storage of the spec
+func uploadsEnvVars(rocket *chatv1alpha1.Rocket) []corev1.EnvVar {
+	uploads := rocket.Spec.Uploads
+	if uploads == nil {
+		return nil
+	}
+	env := []corev1.EnvVar{settingEnvVar("FileUpload_Storage_Type", string(uploads.Type))}
+	switch uploads.Type {
+	case chatv1alpha1.UploadStorageFileSystem:
+		env = append(env, settingEnvVar("FileUpload_FileSystemPath", RocketUploadsPath))
+	case chatv1alpha1.UploadStorageAmazonS3:
+		s3 := uploads.S3
+		if s3 == nil {
+			s3 = &chatv1alpha1.S3Uploads{}
+		}
+		env = append(env,
+			settingEnvVar("FileUpload_S3_Bucket", s3.Bucket),
+			settingEnvVar("FileUpload_S3_Region", s3.Region),
+			settingEnvVar("FileUpload_S3_BucketURL", s3.Endpoint),
+			settingEnvVar("FileUpload_S3_ForcePathStyle", strconv.FormatBool(s3.ForcePathStyle)),
+			settingSecretEnvVar("FileUpload_S3_AWSAccessKeyId", s3.CredentialsSecret, "accessKey"),
+			settingSecretEnvVar("FileUpload_S3_AWSSecretAccessKey", s3.CredentialsSecret, "secretKey"),
+		)
+	}
+	return env
+}
+
+// settingEnvVar overwrites a setting of Rocket.Chat, the setting can't be changed in the administration anymore
+func settingEnvVar(setting, value string) corev1.EnvVar {
+	return corev1.EnvVar{Name: RocketSettingEnvPrefix + setting, Value: value}
+}
+
+func settingSecretEnvVar(setting string, secret corev1.LocalObjectReference, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: RocketSettingEnvPrefix + setting,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: secret, Key: key},
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRocketUploads(t *testing.T) {
+	tests := []struct {
+		name      string
+		uploads   *v1alpha1.RocketUploads
+		wantClaim string
+		wantEnv   map[string]string
+	}{
+		{name: "unset"},
+		{
+			name:    "gridfs",
+			uploads: &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageGridFS},
+			wantEnv: map[string]string{"OVERWRITE_SETTING_FileUpload_Storage_Type": "GridFS"},
+		},
+		{
+			name:      "file system",
+			uploads:   &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageFileSystem, ClaimName: "uploads"},
+			wantClaim: "uploads",
+			wantEnv: map[string]string{
+				"OVERWRITE_SETTING_FileUpload_Storage_Type":   "FileSystem",
+				"OVERWRITE_SETTING_FileUpload_FileSystemPath": "/app/uploads",
+			},
+		},
+		{
+			name: "minio",
+			uploads: &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageAmazonS3, S3: &v1alpha1.S3Uploads{
+				Bucket:            "uploads",
+				Endpoint:          "http://minio.minio:9000",
+				ForcePathStyle:    true,
+				CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+			}},
+			wantEnv: map[string]string{
+				"OVERWRITE_SETTING_FileUpload_Storage_Type":           "AmazonS3",
+				"OVERWRITE_SETTING_FileUpload_S3_Bucket":              "uploads",
+				"OVERWRITE_SETTING_FileUpload_S3_BucketURL":           "http://minio.minio:9000",
+				"OVERWRITE_SETTING_FileUpload_S3_ForcePathStyle":      "true",
+				"OVERWRITE_SETTING_FileUpload_S3_AWSAccessKeyId":      "minio/accessKey",
+				"OVERWRITE_SETTING_FileUpload_S3_AWSSecretAccessKey": "minio/secretKey",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.Uploads = tt.uploads
+			pod := new(RocketDeploymentCreator).CreateResource(rocket).(*appsv1.Deployment).Spec.Template.Spec
+
+			volume := pod.Volumes[0]
+			if tt.wantClaim == "" && volume.EmptyDir == nil {
+				t.Errorf("CreateResource() uploads volume = %v, want an emptyDir", volume.VolumeSource)
+			}
+			if tt.wantClaim != "" && (volume.PersistentVolumeClaim == nil || volume.PersistentVolumeClaim.ClaimName != tt.wantClaim) {
+				t.Errorf("CreateResource() uploads volume = %v, want claim %v", volume.VolumeSource, tt.wantClaim)
+			}
+
+			env := map[string]string{}
+			for _, e := range pod.Containers[0].Env {
+				if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+					env[e.Name] = e.ValueFrom.SecretKeyRef.Name + "/" + e.ValueFrom.SecretKeyRef.Key
+				} else {
+					env[e.Name] = e.Value
+				}
+			}
+			for name, value := range tt.wantEnv {
+				if env[name] != value {
+					t.Errorf("CreateResource() %v = %q, want %q", name, env[name], value)
+				}
+			}
+			if _, ok := env["OVERWRITE_SETTING_FileUpload_Storage_Type"]; tt.uploads == nil && ok {
+				t.Errorf("CreateResource() overwrites the storage type of the administration")
+			}
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package model
 
 import (
-	"reflect"
-
 	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,17 +14,25 @@ func (m *ServiceAccountCreator) Name() string {
 	return "Service Account"
 }
 
-func (c *ServiceAccountCreator) Update(rocket *chatv1alpha1.Rocket, cur client.Object) (client.Object, bool) {
-	update := false
-
-	serviceAccount := cur.(*corev1.ServiceAccount)
-	// check labels
-	if !reflect.DeepEqual(serviceAccount.Labels, rocket.Labels) {
-		serviceAccount.Labels = rocket.Labels
-		update = true
+func (c *ServiceAccountCreator) Update(desired, cur client.Object) (client.Object, []string) {
+	// the token controller adds its own secrets to the service account,
+	// so only check that the desired secrets are referenced
+	drifted := DriftedFields(desired, cur, "secrets")
+	for _, secret := range desired.(*corev1.ServiceAccount).Secrets {
+		if !containsObjectReference(cur.(*corev1.ServiceAccount).Secrets, secret) {
+			drifted = append(drifted, "secrets")
+		}
 	}
+	return desired, drifted
+}
 
-	return serviceAccount, update
+func containsObjectReference(references []corev1.ObjectReference, reference corev1.ObjectReference) bool {
+	for _, r := range references {
+		if r.Name == reference.Name {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *ServiceAccountCreator) CreateResource(r *chatv1alpha1.Rocket) client.Object {
@@ -49,3 +55,13 @@ func (c *ServiceAccountCreator) Selector(r *chatv1alpha1.Rocket) client.ObjectKe
 		Namespace: r.Namespace,
 	}
 }
+
+// DependsOn returns the creators of the resources the ServiceAccountCreator depends on
+func (c *ServiceAccountCreator) DependsOn() []ResourceCreator {
+	return []ResourceCreator{new(MongodbAuthSecretCreator)}
+}
+
+// IsWanted returns true, the pods of every rocket run with the service account
+func (c *ServiceAccountCreator) IsWanted(rocket *chatv1alpha1.Rocket) bool {
+	return true
+}
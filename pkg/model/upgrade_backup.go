@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpgradeBackupTarget returns the target of the backup taken before a new version of Rocket.Chat is rolled out,
+// nil if neither the upgrade strategy nor the scheduled backups configure one
+func UpgradeBackupTarget(rocket *chatv1alpha1.Rocket) *chatv1alpha1.BackupTarget {
+	if target := rocket.Spec.UpgradeStrategy.BackupTarget; target != nil {
+		return target
+	}
+	if rocket.Spec.Backup != nil {
+		return &rocket.Spec.Backup.Target
+	}
+	return nil
+}
+
+// UpgradeBackup returns the backup of the database taken before the pending version change of Rocket.Chat.
+// Its name contains the generation the change was detected in, a backup of an earlier upgrade to the same version is never reused.
+func UpgradeBackup(rocket *chatv1alpha1.Rocket, pending *chatv1alpha1.PendingUpgradeStatus, target chatv1alpha1.BackupTarget) *chatv1alpha1.RocketBackup {
+	return &chatv1alpha1.RocketBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v%v%v-%v", rocket.Name, RocketUpgradeBackupInfix, pending.ToVersion, pending.Generation),
+			Namespace: rocket.Namespace,
+			Labels:    rocket.Labels,
+		},
+		Spec: chatv1alpha1.RocketBackupSpec{
+			Rocket: rocket.Name,
+			Target: target,
+		},
+	}
+}
+
+// RollbackRestore returns the restore of the backup taken before the failed version of Rocket.Chat was rolled out.
+// Its name contains the start of the rollback, so every rollback of a version restores the database again.
+func RollbackRestore(rocket *chatv1alpha1.Rocket, rollback *chatv1alpha1.RollbackStatus, backup *chatv1alpha1.UpgradeBackupStatus) *chatv1alpha1.RocketRestore {
+	var started int64
+	if rollback.StartTime != nil {
+		started = rollback.StartTime.Unix()
+	}
+	return &chatv1alpha1.RocketRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v%v%v-%v", rocket.Name, RocketRollbackRestoreInfix, backup.ToVersion, started),
+			Namespace: rocket.Namespace,
+			Labels:    rocket.Labels,
+		},
+		Spec: chatv1alpha1.RocketRestoreSpec{
+			Rocket: rocket.Name,
+			Source: chatv1alpha1.RestoreSource{
+				ClaimName: backup.Target.ClaimName,
+				S3:        backup.Target.S3,
+				Archive:   backup.Archive,
+			},
+		},
+	}
+}
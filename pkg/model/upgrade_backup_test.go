@@ -0,0 +1,97 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpgradeBackupTarget(t *testing.T) {
+	scheduled := &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *", Target: v1alpha1.BackupTarget{ClaimName: "scheduled"}}
+	tests := []struct {
+		name      string
+		backup    *v1alpha1.RocketBackupSchedule
+		strategy  v1alpha1.RocketUpgradeStrategy
+		wantClaim string
+	}{
+		{name: "no target"},
+		{name: "scheduled backups", backup: scheduled, wantClaim: "scheduled"},
+		{
+			name:      "upgrade strategy",
+			backup:    scheduled,
+			strategy:  v1alpha1.RocketUpgradeStrategy{BackupTarget: &v1alpha1.BackupTarget{ClaimName: "upgrades"}},
+			wantClaim: "upgrades",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := testRocket()
+			rocket.Spec.Backup = tt.backup
+			rocket.Spec.UpgradeStrategy = tt.strategy
+			target := UpgradeBackupTarget(rocket)
+			if tt.wantClaim == "" {
+				if target != nil {
+					t.Errorf("UpgradeBackupTarget() = %v, want nil", target)
+				}
+				return
+			}
+			if target == nil || target.ClaimName != tt.wantClaim {
+				t.Errorf("UpgradeBackupTarget() = %v, want claim %v", target, tt.wantClaim)
+			}
+		})
+	}
+}
+
+func TestUpgradeBackup(t *testing.T) {
+	rocket := testRocket()
+	target := v1alpha1.BackupTarget{ClaimName: "upgrades"}
+	first := UpgradeBackup(rocket, &v1alpha1.PendingUpgradeStatus{ToVersion: "4.0.0", Generation: 2}, target)
+	if first.Name != "test-upgrade-4.0.0-2" || first.Spec.Target != target {
+		t.Errorf("UpgradeBackup() = %v with target %v", first.Name, first.Spec.Target)
+	}
+	// upgrading to the same version again after a rollback takes a new backup
+	again := UpgradeBackup(rocket, &v1alpha1.PendingUpgradeStatus{ToVersion: "4.0.0", Generation: 5}, target)
+	if again.Name == first.Name {
+		t.Errorf("UpgradeBackup() of a repeated upgrade reuses %v", first.Name)
+	}
+}
+
+func TestRocketWebserverVersion(t *testing.T) {
+	rocket := testRocket()
+	rocket.Spec.Version = "4.0.0"
+	if got := RocketWebserverVersion(rocket); got != "4.0.0" {
+		t.Errorf("RocketWebserverVersion() of a new rocket = %v, want 4.0.0", got)
+	}
+	// the new version is held back until the controller rolls it out
+	rocket.Status.WebserverVersion = "3.18.2"
+	if got := RocketWebserverVersion(rocket); got != "3.18.2" {
+		t.Errorf("RocketWebserverVersion() = %v, want 3.18.2", got)
+	}
+}
+
+func TestRollbackRestore(t *testing.T) {
+	rocket := testRocket()
+	backup := &v1alpha1.UpgradeBackupStatus{
+		FromVersion: "3.18.2",
+		ToVersion:   "4.0.0",
+		Backup:      "test-upgrade-4.0.0",
+		Target:      v1alpha1.BackupTarget{ClaimName: "upgrades"},
+		Archive:     "test-upgrade-4.0.0.archive.gz",
+	}
+	started := v1.NewTime(time.Unix(1640995200, 0))
+	restore := RollbackRestore(rocket, &v1alpha1.RollbackStatus{FromVersion: "4.0.0", StartTime: &started}, backup)
+
+	if restore.Name != "test-rollback-4.0.0-1640995200" || restore.Spec.Rocket != rocket.Name {
+		t.Errorf("RollbackRestore() = %v for rocket %v", restore.Name, restore.Spec.Rocket)
+	}
+	if source := restore.Spec.Source; source.ClaimName != "upgrades" || source.Archive != backup.Archive {
+		t.Errorf("RollbackRestore() source = %v", source)
+	}
+	// a later rollback of the same version doesn't find the completed restore of the first one
+	again := v1.NewTime(started.Add(time.Hour))
+	if second := RollbackRestore(rocket, &v1alpha1.RollbackStatus{FromVersion: "4.0.0", StartTime: &again}, backup); second.Name == restore.Name {
+		t.Errorf("RollbackRestore() of a second rollback reuses %v", restore.Name)
+	}
+}
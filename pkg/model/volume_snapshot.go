@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VolumeSnapshotGVK is the kind of the snapshots taken by the Snapshot deletion policy
+var VolumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// IsDatabaseClaim returns true if the claim was created from the volumeClaimTemplate of the mongodb statefulSet.
+// The statefulSet controller names these claims <template name>-<statefulSet name>-<ordinal>.
+func IsDatabaseClaim(rocket *chatv1alpha1.Rocket, claim *corev1.PersistentVolumeClaim) bool {
+	if rocket.Spec.Database.StorageSpec == nil || claim.Namespace != rocket.Namespace {
+		return false
+	}
+	prefix := fmt.Sprintf("%v-%v-", rocket.Spec.Database.StorageSpec.Name, rocket.Name+MongodbStatefulSetSuffix)
+	ordinal := strings.TrimPrefix(claim.Name, prefix)
+	if ordinal == claim.Name || ordinal == "" {
+		return false
+	}
+	for _, c := range ordinal {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// VolumeSnapshot returns a snapshot of the given database claim.
+// The snapshot isn't owned by the rocket, so it outlives the deletion of the rocket.
+func VolumeSnapshot(rocket *chatv1alpha1.Rocket, claim *corev1.PersistentVolumeClaim) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetName(claim.Name + MongodbVolumeSnapshotSuffix)
+	snapshot.SetNamespace(claim.Namespace)
+	snapshot.SetLabels(rocket.Labels)
+	snapshot.SetAnnotations(map[string]string{RetainedFromAnnotation: rocket.Name})
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": claim.Name,
+		},
+	}
+	if class := rocket.Spec.Database.VolumeSnapshotClassName; class != "" {
+		spec["volumeSnapshotClassName"] = class
+	}
+	snapshot.Object["spec"] = spec
+	return snapshot
+}
+
+// IsVolumeSnapshotReady returns whether the snapshot is ready to use and the error reported by the snapshotter
+func IsVolumeSnapshotReady(snapshot *unstructured.Unstructured) (bool, error) {
+	if msg, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found && msg != "" {
+		return false, fmt.Errorf("Error taking volume snapshot %v: %v", snapshot.GetName(), msg)
+	}
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return ready, nil
+}
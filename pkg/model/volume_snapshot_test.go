@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDatabaseClaim(t *testing.T) {
+	tests := []struct {
+		name      string
+		claimName string
+		namespace string
+		want      bool
+	}{
+		{name: "first replica", claimName: "test-datadir-test-mongodb-0", namespace: "default", want: true},
+		{name: "third replica", claimName: "test-datadir-test-mongodb-2", namespace: "default", want: true},
+		{name: "other namespace", claimName: "test-datadir-test-mongodb-0", namespace: "other"},
+		{name: "other statefulSet", claimName: "test-datadir-other-mongodb-0", namespace: "default"},
+		{name: "no ordinal", claimName: "test-datadir-test-mongodb-", namespace: "default"},
+		{name: "suffix after ordinal", claimName: "test-datadir-test-mongodb-0-backup", namespace: "default"},
+	}
+	rocket := testRocket()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := &corev1.PersistentVolumeClaim{ObjectMeta: v1.ObjectMeta{Name: tt.claimName, Namespace: tt.namespace}}
+			if got := IsDatabaseClaim(rocket, claim); got != tt.want {
+				t.Errorf("IsDatabaseClaim() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
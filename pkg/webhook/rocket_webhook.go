@@ -0,0 +1,507 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	webhookLog = ctrl.Log.WithName("webhooks").WithName("Rocket")
+
+	rocketGroupKind = schema.GroupKind{Group: chatv1alpha1.SchemeGroupVersion.Group, Kind: "Rocket"}
+
+	// webserverVersionRegex matches tags of the rocketchat/rocket.chat image, e.g. 3.18 or 3.18.2
+	webserverVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+(\.[0-9]+)?$`)
+	// databaseVersionRegex matches tags of the bitnami/mongodb image, e.g. 4.4.10 or 4.4.10-debian-10-r20
+	databaseVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+(\.[0-9]+)?(-[0-9A-Za-z.-]+)?$`)
+	cronMacroRegex       = regexp.MustCompile(`^@(yearly|annually|monthly|weekly|daily|midnight|hourly)$`)
+	cronFieldRegex       = regexp.MustCompile(`^[0-9A-Za-z*/,?-]+$`)
+)
+
+//+kubebuilder:webhook:path=/mutate-chat-accso-de-v1alpha1-rocket,mutating=true,failurePolicy=fail,sideEffects=None,groups=chat.accso.de,resources=rockets,verbs=create;update,versions=v1alpha1,name=mrocket.chat.accso.de,admissionReviewVersions=v1
+
+// RocketDefaulter sets default values on Rocket objects on admission
+type RocketDefaulter struct{}
+
+//+kubebuilder:webhook:path=/validate-chat-accso-de-v1alpha1-rocket,mutating=false,failurePolicy=fail,sideEffects=None,groups=chat.accso.de,resources=rockets,verbs=create;update,versions=v1alpha1,name=vrocket.chat.accso.de,admissionReviewVersions=v1
+
+// RocketValidator validates Rocket objects on admission
+type RocketValidator struct {
+	// catalog decides which versions of Rocket.Chat and mongodb can be combined, the embedded catalog is used if it is nil
+	catalog *model.CompatibilityCatalog
+}
+
+// SetupRocketWebhookWithManager registers the Rocket webhooks at the webhook server of the manager
+func SetupRocketWebhookWithManager(mgr ctrl.Manager, catalog *model.CompatibilityCatalog) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&chatv1alpha1.Rocket{}).
+		WithDefaulter(new(RocketDefaulter)).
+		WithValidator(&RocketValidator{catalog: catalog}).
+		Complete()
+}
+
+// Default fills versions, replicas, storage and labels of the Rocket if they are unset
+func (d *RocketDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	rocket, ok := obj.(*chatv1alpha1.Rocket)
+	if !ok {
+		return fmt.Errorf("expected a Rocket but got a %T", obj)
+	}
+	webhookLog.V(1).Info("default", "object", rocket.Name)
+
+	model.SetRocketDefaults(rocket)
+	return nil
+}
+
+// ValidateCreate validates the spec of a new Rocket
+func (v *RocketValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	rocket, ok := obj.(*chatv1alpha1.Rocket)
+	if !ok {
+		return fmt.Errorf("expected a Rocket but got a %T", obj)
+	}
+	webhookLog.V(1).Info("validate create", "object", rocket.Name)
+
+	allErrs := validateRocketSpec(rocket)
+	allErrs = append(allErrs, v.validateCompatibility(rocket)...)
+	return toInvalidError(rocket, allErrs)
+}
+
+// ValidateUpdate validates the spec of an updated Rocket and blocks updates that can't be applied safely
+func (v *RocketValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldRocket, ok := oldObj.(*chatv1alpha1.Rocket)
+	if !ok {
+		return fmt.Errorf("expected a Rocket but got a %T", oldObj)
+	}
+	rocket, ok := newObj.(*chatv1alpha1.Rocket)
+	if !ok {
+		return fmt.Errorf("expected a Rocket but got a %T", newObj)
+	}
+	webhookLog.V(1).Info("validate update", "object", rocket.Name)
+
+	allErrs := validateRocketSpec(rocket)
+	allErrs = append(allErrs, validateRocketUpdate(oldRocket, rocket)...)
+	// rockets which became incompatible with a newer catalog can still be changed, as long as their versions stay the same
+	if oldRocket.Spec.Version != rocket.Spec.Version || oldRocket.Spec.Database.Version != rocket.Spec.Database.Version {
+		allErrs = append(allErrs, v.validateCompatibility(rocket)...)
+	}
+	return toInvalidError(rocket, allErrs)
+}
+
+// validateCompatibility refuses versions of Rocket.Chat which don't support the version of mongodb
+func (v *RocketValidator) validateCompatibility(rocket *chatv1alpha1.Rocket) field.ErrorList {
+	var allErrs field.ErrorList
+	version, databaseVersion := rocket.Spec.Version, rocket.Spec.Database.Version
+	if version == "" || databaseVersion == "" {
+		return allErrs
+	}
+	catalog := v.catalog
+	if catalog == nil {
+		catalog = model.DefaultCompatibilityCatalog()
+	}
+	if err := catalog.CheckCompatibility(version, databaseVersion); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), version, err.Error()))
+	}
+	return allErrs
+}
+
+// ValidateDelete allows every deletion
+func (v *RocketValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func toInvalidError(rocket *chatv1alpha1.Rocket, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apiErrors.NewInvalid(rocketGroupKind, rocket.Name, allErrs)
+}
+
+func validateRocketSpec(rocket *chatv1alpha1.Rocket) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	spec := rocket.Spec
+
+	if spec.Replicas != nil && *spec.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), *spec.Replicas, "must be greater than or equal to 0"))
+	}
+	if spec.Version != "" && !webserverVersionRegex.MatchString(spec.Version) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("version"), spec.Version, "must be a Rocket.Chat release like 3.18.2"))
+	}
+	allErrs = append(allErrs, validateAdminSpec(spec.AdminSpec, specPath.Child("adminSpec"))...)
+	allErrs = append(allErrs, validateDatabase(spec.Database, specPath.Child("database"))...)
+	allErrs = append(allErrs, validatePodTemplate(spec.PodTemplate, specPath.Child("podTemplate"))...)
+	allErrs = append(allErrs, validatePodTemplate(spec.Database.PodTemplate, specPath.Child("database", "podTemplate"))...)
+	allErrs = append(allErrs, validateIngressSpec(spec.IngressSpec, specPath.Child("ingressSpec"))...)
+	allErrs = append(allErrs, validateBackupSchedule(spec.Backup, specPath.Child("backup"))...)
+	allErrs = append(allErrs, validateOverrides(spec.Overrides, specPath.Child("overrides"))...)
+	allErrs = append(allErrs, validateUploads(spec.Uploads, specPath.Child("uploads"))...)
+	allErrs = append(allErrs, validateExposure(rocket, specPath)...)
+	allErrs = append(allErrs, validateSettings(rocket, specPath)...)
+	if target := spec.UpgradeStrategy.BackupTarget; target != nil {
+		allErrs = append(allErrs, validateBackupTarget(*target, specPath.Child("upgradeStrategy", "backupTarget"))...)
+	}
+	return allErrs
+}
+
+func validateAdminSpec(admin *chatv1alpha1.RocketAdminSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if admin == nil {
+		return append(allErrs, field.Required(path, "admin username and email are required"))
+	}
+	if admin.Username == "" {
+		allErrs = append(allErrs, field.Required(path.Child("username"), ""))
+	}
+	if admin.Email == "" {
+		allErrs = append(allErrs, field.Required(path.Child("email"), ""))
+	} else if address, err := mail.ParseAddress(admin.Email); err != nil || address.Address != admin.Email {
+		allErrs = append(allErrs, field.Invalid(path.Child("email"), admin.Email, "must be a valid email address"))
+	}
+	return allErrs
+}
+
+func validateDatabase(database chatv1alpha1.RocketDatabase, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if database.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("replicas"), database.Replicas, "must be greater than or equal to 0"))
+	} else if database.Replicas%2 == 0 && database.Replicas != 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("replicas"), database.Replicas, "must be an odd number to allow the replica set to elect a primary"))
+	}
+	if database.Version != "" && !databaseVersionRegex.MatchString(database.Version) {
+		allErrs = append(allErrs, field.Invalid(path.Child("version"), database.Version, "must be a bitnami/mongodb tag like 4.4.10"))
+	}
+	if database.External != nil {
+		// no volumes are claimed for an external database
+		if database.External.SecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("external", "secretRef", "name"), "a secret with the keys uri and oplog-uri is required"))
+		}
+		return allErrs
+	}
+
+	// a missing claim template is defaulted
+	if database.StorageSpec == nil {
+		return allErrs
+	}
+	storagePath := path.Child("storageSpec")
+	if storage, ok := database.StorageSpec.Spec.Resources.Requests[corev1.ResourceStorage]; ok && storage.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(storagePath.Child("spec", "resources", "requests", "storage"), storage.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
+
+// validatePodTemplate checks that no request exceeds its limit, the pods would be rejected otherwise
+func validatePodTemplate(overrides chatv1alpha1.PodTemplateOverrides, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if overrides.Resources == nil {
+		return allErrs
+	}
+	resourcesPath := path.Child("resources")
+	for name, request := range overrides.Resources.Requests {
+		if limit, ok := overrides.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(resourcesPath.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must be less than or equal to the limit %v", limit.String())))
+		}
+	}
+	return allErrs
+}
+
+func validateOverrides(overrides []chatv1alpha1.ResourceOverride, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, override := range overrides {
+		overridePath := path.Index(i)
+		if override.Kind == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("kind"), ""))
+		}
+		if strings.TrimSpace(override.Patch) == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("patch"), ""))
+			continue
+		}
+		if err := model.ValidateOverride(override); err != nil {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("patch"), override.Patch, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateUploads checks that the storage of the type is configured and no other storage is set
+func validateUploads(uploads *chatv1alpha1.RocketUploads, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if uploads == nil {
+		return allErrs
+	}
+	claimPath, s3Path := path.Child("claimName"), path.Child("s3")
+	if uploads.Type == chatv1alpha1.UploadStorageFileSystem {
+		if uploads.ClaimName == "" {
+			allErrs = append(allErrs, field.Required(claimPath, "the FileSystem storage requires a claim"))
+		}
+	} else if uploads.ClaimName != "" {
+		allErrs = append(allErrs, field.Forbidden(claimPath, "only the FileSystem storage uses a claim"))
+	}
+	if uploads.Type != chatv1alpha1.UploadStorageAmazonS3 {
+		if uploads.S3 != nil {
+			allErrs = append(allErrs, field.Forbidden(s3Path, "only the AmazonS3 storage uses a bucket"))
+		}
+		return allErrs
+	}
+
+	s3 := uploads.S3
+	if s3 == nil {
+		return append(allErrs, field.Required(s3Path, "the AmazonS3 storage requires a bucket"))
+	}
+	if s3.Bucket == "" {
+		allErrs = append(allErrs, field.Required(s3Path.Child("bucket"), ""))
+	}
+	if s3.Endpoint != "" {
+		if endpoint, err := url.Parse(s3.Endpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			allErrs = append(allErrs, field.Invalid(s3Path.Child("endpoint"), s3.Endpoint, "must be a http or https URL"))
+		}
+	}
+	if s3.CredentialsSecret.Name == "" {
+		allErrs = append(allErrs, field.Required(s3Path.Child("credentialsSecret", "name"), ""))
+	}
+	return allErrs
+}
+
+func validateIngressSpec(ingress chatv1alpha1.RocketIngressSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ingress.Path != "" && (!strings.HasPrefix(ingress.Path, "/") || strings.ContainsAny(ingress.Path, "?# \t")) {
+		allErrs = append(allErrs, field.Invalid(path.Child("path"), ingress.Path, "must be an absolute path like /chat without query or fragment"))
+	}
+	allErrs = append(allErrs, validateIngressTLS(ingress, path)...)
+	if ingress.Host == "" {
+		return allErrs
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(ingress.Host) {
+		allErrs = append(allErrs, field.Invalid(path.Child("host"), ingress.Host, msg))
+	}
+	return allErrs
+}
+
+func validateIngressTLS(ingress chatv1alpha1.RocketIngressSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	tls := ingress.TLS
+	if tls == nil {
+		return allErrs
+	}
+	tlsPath := path.Child("tls")
+	secretPath, issuerPath := tlsPath.Child("secretName"), tlsPath.Child("issuerRef")
+	if tls.Mode == chatv1alpha1.IngressTLSNone && tls.SecretName != "" {
+		allErrs = append(allErrs, field.Forbidden(secretPath, "no certificate is used without TLS"))
+	}
+	if tls.Mode == chatv1alpha1.IngressTLSNone && tls.Termination != "" {
+		allErrs = append(allErrs, field.Forbidden(tlsPath.Child("termination"), "TLS isn't terminated without TLS"))
+	}
+	if tls.SecretName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(tls.SecretName) {
+			allErrs = append(allErrs, field.Invalid(secretPath, tls.SecretName, msg))
+		}
+	}
+	if tls.Mode != chatv1alpha1.IngressTLSIssuer {
+		if tls.IssuerRef != nil {
+			allErrs = append(allErrs, field.Forbidden(issuerPath, "only the Issuer mode requests a certificate from an issuer"))
+		}
+		return allErrs
+	}
+
+	if ingress.Host == "" {
+		allErrs = append(allErrs, field.Required(path.Child("host"), "the certificate is issued for the host"))
+	}
+	issuer := tls.IssuerRef
+	if issuer == nil {
+		return append(allErrs, field.Required(issuerPath, "the Issuer mode requires an issuer"))
+	}
+	if issuer.Name == "" {
+		allErrs = append(allErrs, field.Required(issuerPath.Child("name"), ""))
+	}
+	// issuers of other groups are external issuers with their own kinds
+	if (issuer.Group == "" || issuer.Group == "cert-manager.io") && issuer.Kind != "" && issuer.Kind != "Issuer" && issuer.Kind != "ClusterIssuer" {
+		allErrs = append(allErrs, field.NotSupported(issuerPath.Child("kind"), issuer.Kind, []string{"Issuer", "ClusterIssuer"}))
+	}
+	return allErrs
+}
+
+func validateExposure(rocket *chatv1alpha1.Rocket, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	exposure := rocket.ExposureType()
+	if tls := rocket.Spec.IngressSpec.TLS; tls != nil && tls.Mode == chatv1alpha1.IngressTLSIssuer && exposure != chatv1alpha1.ExposureIngress {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("ingressSpec", "tls", "mode"), "certificates are only issued for the Ingress exposure"))
+	}
+	if rocket.Spec.Exposure == nil {
+		return allErrs
+	}
+	gatewayPath := specPath.Child("exposure", "gateway")
+	gateway := rocket.Spec.Exposure.Gateway
+	if exposure != chatv1alpha1.ExposureGatewayHTTPRoute {
+		if gateway != nil {
+			allErrs = append(allErrs, field.Forbidden(gatewayPath, "only the GatewayHTTPRoute exposure is attached to a gateway"))
+		}
+		return allErrs
+	}
+	if gateway == nil {
+		return append(allErrs, field.Required(gatewayPath, "the GatewayHTTPRoute exposure requires a gateway"))
+	}
+	if gateway.Name == "" {
+		allErrs = append(allErrs, field.Required(gatewayPath.Child("name"), ""))
+	}
+	return allErrs
+}
+
+// validateSettings refuses settings which are unknown or already overwritten by the operator.
+// The keys of spec.settingsFrom are read at runtime and can't be validated here.
+func validateSettings(rocket *chatv1alpha1.Rocket, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	settingsPath := specPath.Child("settings")
+	for id := range rocket.Spec.Settings {
+		idPath := settingsPath.Key(id)
+		if msgs := validation.IsEnvVarName(model.RocketSettingEnvPrefix + id); len(msgs) > 0 {
+			allErrs = append(allErrs, field.Invalid(idPath, id, strings.Join(msgs, "; ")))
+			continue
+		}
+		if model.IsManagedSetting(rocket, id) {
+			allErrs = append(allErrs, field.Forbidden(idPath, "the setting is managed by the operator"))
+			continue
+		}
+		if !model.IsKnownSetting(id) {
+			allErrs = append(allErrs, field.Invalid(idPath, id, "must be a known setting of Rocket.Chat, other settings can be overwritten with settingsFrom"))
+		}
+	}
+	for i, source := range rocket.Spec.SettingsFrom {
+		sourcePath := specPath.Child("settingsFrom").Index(i)
+		switch {
+		case source.SecretRef == nil && source.ConfigMapRef == nil:
+			allErrs = append(allErrs, field.Required(sourcePath, "either secretRef or configMapRef is required"))
+		case source.SecretRef != nil && source.ConfigMapRef != nil:
+			allErrs = append(allErrs, field.Forbidden(sourcePath, "only one of secretRef and configMapRef may be set"))
+		case source.SecretRef != nil && source.SecretRef.Name == "":
+			allErrs = append(allErrs, field.Required(sourcePath.Child("secretRef", "name"), ""))
+		case source.ConfigMapRef != nil && source.ConfigMapRef.Name == "":
+			allErrs = append(allErrs, field.Required(sourcePath.Child("configMapRef", "name"), ""))
+		}
+	}
+	return allErrs
+}
+
+func validateBackupSchedule(backup *chatv1alpha1.RocketBackupSchedule, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if backup == nil {
+		return allErrs
+	}
+	if !isCronSchedule(backup.Schedule) {
+		allErrs = append(allErrs, field.Invalid(path.Child("schedule"), backup.Schedule, "must be a cron schedule with five fields like \"0 2 * * *\" or a macro like @daily"))
+	}
+	allErrs = append(allErrs, validateBackupTarget(backup.Target, path.Child("target"))...)
+
+	retentionPath := path.Child("retention")
+	if backup.Retention.Count < 0 {
+		allErrs = append(allErrs, field.Invalid(retentionPath.Child("count"), backup.Retention.Count, "must be greater than or equal to 0"))
+	}
+	if maxAge := backup.Retention.MaxAge; maxAge != nil && maxAge.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(retentionPath.Child("maxAge"), maxAge.Duration.String(), "must be at least one minute"))
+	}
+	return allErrs
+}
+
+// validateBackupTarget checks that exactly one target is set
+func validateBackupTarget(target chatv1alpha1.BackupTarget, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case target.ClaimName == "" && target.S3 == nil:
+		allErrs = append(allErrs, field.Required(path, "either claimName or s3 is required"))
+	case target.ClaimName != "" && target.S3 != nil:
+		allErrs = append(allErrs, field.Forbidden(path, "only one of claimName and s3 may be set"))
+	case target.S3 != nil:
+		s3Path := path.Child("s3")
+		if endpoint, err := url.Parse(target.S3.Endpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			allErrs = append(allErrs, field.Invalid(s3Path.Child("endpoint"), target.S3.Endpoint, "must be a http or https URL"))
+		}
+		if target.S3.Bucket == "" {
+			allErrs = append(allErrs, field.Required(s3Path.Child("bucket"), ""))
+		}
+		if target.S3.CredentialsSecret.Name == "" {
+			allErrs = append(allErrs, field.Required(s3Path.Child("credentialsSecret", "name"), ""))
+		}
+	}
+	return allErrs
+}
+
+// isCronSchedule checks the format of a cron schedule, the values of the fields are checked by the cronJob controller
+func isCronSchedule(schedule string) bool {
+	if strings.HasPrefix(schedule, "@") {
+		return cronMacroRegex.MatchString(schedule)
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, f := range fields {
+		if !cronFieldRegex.MatchString(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRocketUpdate checks for changes that would lose data or can't be applied to the existing resources
+func validateRocketUpdate(oldRocket, rocket *chatv1alpha1.Rocket) field.ErrorList {
+	var allErrs field.ErrorList
+	if oldRocket.HasExternalDatabase() != rocket.HasExternalDatabase() {
+		// the data isn't migrated between the replica set of the operator and an external database
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "database", "external"),
+			"switching between an external database and a database deployed by the operator is not supported"))
+	}
+	if oldRocket.Spec.Version != "" && oldRocket.Spec.Version != rocket.Spec.Version &&
+		!rocket.Spec.UpgradeStrategy.SkipBackup && model.UpgradeBackupTarget(rocket) == nil {
+		// the controller only rolls out a new version after a backup of the database was taken
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "version"),
+			"changing the version requires a backup target, set spec.upgradeStrategy.backupTarget, spec.backup or spec.upgradeStrategy.skipBackup"))
+	}
+	if rocket.HasExternalDatabase() {
+		return allErrs
+	}
+
+	oldVersion, version := oldRocket.Spec.Database.Version, rocket.Spec.Database.Version
+	if oldRocket.IsDatabaseUpgradeFailed() {
+		// the featureCompatibilityVersion wasn't raised yet, so a failed upgrade can be rolled back to the series it started from
+		if major, err := model.IsMongodbMajorUpgrade(oldRocket.Status.DatabaseUpgrade.FromVersion, version); err == nil && !major {
+			oldVersion = ""
+		}
+	}
+	if oldVersion != "" && version != "" {
+		// the controller rolls major upgrades one release series at a time
+		if _, err := model.IsMongodbMajorUpgrade(oldVersion, version); err != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "database", "version"), err.Error()))
+		}
+	}
+
+	oldStorage, storage := oldRocket.Spec.Database.StorageSpec, rocket.Spec.Database.StorageSpec
+	if oldStorage == nil || storage == nil {
+		return allErrs
+	}
+	storagePath := field.NewPath("spec", "database", "storageSpec", "spec")
+
+	oldSize, oldOk := oldStorage.Spec.Resources.Requests[corev1.ResourceStorage]
+	size, ok := storage.Spec.Resources.Requests[corev1.ResourceStorage]
+	if oldOk && ok && size.Cmp(oldSize) < 0 {
+		allErrs = append(allErrs, field.Forbidden(storagePath.Child("resources", "requests", "storage"),
+			fmt.Sprintf("shrinking the database storage from %v to %v is not supported", oldSize.String(), size.String())))
+	}
+
+	oldClass, class := oldStorage.Spec.StorageClassName, storage.Spec.StorageClassName
+	if oldClass != nil && (class == nil || *class != *oldClass) {
+		allErrs = append(allErrs, field.Forbidden(storagePath.Child("storageClassName"), "the storage class of the database can't be changed"))
+	}
+	return allErrs
+}
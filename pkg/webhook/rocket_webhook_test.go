@@ -0,0 +1,548 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	"github.com/bachelor-thesis-hown3d/chat-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validRocket() *v1alpha1.Rocket {
+	return &v1alpha1.Rocket{
+		ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: v1alpha1.RocketSpec{
+			Version: "3.18.2",
+			AdminSpec: &v1alpha1.RocketAdminSpec{
+				Username: "admin",
+				Email:    "admin@example.com",
+			},
+			Database: v1alpha1.RocketDatabase{
+				Version:  "4.4.10",
+				Replicas: 3,
+				StorageSpec: &v1alpha1.EmbeddedPersistentVolumeClaim{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+						},
+					},
+				},
+			},
+			IngressSpec: v1alpha1.RocketIngressSpec{Host: "chat.example.com"},
+		},
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(r *v1alpha1.Rocket)
+		wantErr bool
+	}{
+		{
+			name:   "valid rocket",
+			mutate: func(r *v1alpha1.Rocket) {},
+		},
+		{
+			name:    "missing admin spec",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.AdminSpec = nil },
+			wantErr: true,
+		},
+		{
+			name:    "missing admin username",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.AdminSpec.Username = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid admin email",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.AdminSpec.Email = "admin" },
+			wantErr: true,
+		},
+		{
+			name:   "missing storage spec",
+			mutate: func(r *v1alpha1.Rocket) { r.Spec.Database.StorageSpec = nil },
+		},
+		{
+			name:    "even database replicas",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Database.Replicas = 2 },
+			wantErr: true,
+		},
+		{
+			name:   "unset database replicas",
+			mutate: func(r *v1alpha1.Rocket) { r.Spec.Database.Replicas = 0 },
+		},
+		{
+			name:    "malformed webserver version",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Version = "latest" },
+			wantErr: true,
+		},
+		{
+			name:   "bitnami database version",
+			mutate: func(r *v1alpha1.Rocket) { r.Spec.Database.Version = "4.4.10-debian-10-r20" },
+		},
+		{
+			name:    "malformed database version",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Database.Version = "four" },
+			wantErr: true,
+		},
+		{
+			name: "backup to a claim",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *", Target: v1alpha1.BackupTarget{ClaimName: "backups"}}
+			},
+		},
+		{
+			name: "backup to a bucket",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "@daily", Target: v1alpha1.BackupTarget{S3: &v1alpha1.S3Target{
+					Endpoint:          "http://minio.minio:9000",
+					Bucket:            "backups",
+					CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+				}}}
+			},
+		},
+		{
+			name: "backup without target",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "0 2 * * *"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed backup schedule",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Backup = &v1alpha1.RocketBackupSchedule{Schedule: "every night", Target: v1alpha1.BackupTarget{ClaimName: "backups"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "supported version pair",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Version = "6.0.0"
+				r.Spec.Database.Version = "5.0.3"
+			},
+		},
+		{
+			name: "database too old for the webserver",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Version = "6.0.0"
+				r.Spec.Database.Version = "4.2.17"
+			},
+			wantErr: true,
+		},
+		{
+			name: "upgrade backup target with claim and bucket",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.UpgradeStrategy.BackupTarget = &v1alpha1.BackupTarget{ClaimName: "backups", S3: &v1alpha1.S3Target{
+					Endpoint:          "http://minio.minio:9000",
+					Bucket:            "backups",
+					CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+				}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "external database without storage",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Database.External = &v1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "atlas"}}
+				r.Spec.Database.StorageSpec = nil
+			},
+		},
+		{
+			name:    "external database without secret",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Database.External = &v1alpha1.ExternalDatabase{} },
+			wantErr: true,
+		},
+		{
+			name: "database request above its limit",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Database.PodTemplate.Resources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "override adding an annotation",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Overrides = []v1alpha1.ResourceOverride{{Kind: "Deployment", Patch: "metadata:\n  annotations:\n    team: chat"}}
+			},
+		},
+		{
+			name: "override changing a selector",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Overrides = []v1alpha1.ResourceOverride{{
+					Kind:  "Service",
+					Type:  v1alpha1.OverrideJSONPatch,
+					Patch: `[{"op": "replace", "path": "/spec/selector/app", "value": "other"}]`,
+				}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "uploads to minio",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Uploads = &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageAmazonS3, S3: &v1alpha1.S3Uploads{
+					Bucket:            "uploads",
+					Endpoint:          "http://minio.minio:9000",
+					ForcePathStyle:    true,
+					CredentialsSecret: corev1.LocalObjectReference{Name: "minio"},
+				}}
+			},
+		},
+		{
+			name: "uploads to the file system without claim",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Uploads = &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageFileSystem}
+			},
+			wantErr: true,
+		},
+		{
+			name: "uploads to gridfs with a claim",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Uploads = &v1alpha1.RocketUploads{Type: v1alpha1.UploadStorageGridFS, ClaimName: "uploads"}
+			},
+			wantErr: true,
+		},
+		{
+			name:   "ingress path prefix",
+			mutate: func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Path = "/chat" },
+		},
+		{
+			name:    "relative ingress path",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Path = "chat" },
+			wantErr: true,
+		},
+		{
+			name:    "malformed host",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.IngressSpec.Host = "Chat_Example" },
+			wantErr: true,
+		},
+		{
+			name: "tls from an existing secret",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{Mode: v1alpha1.IngressTLSSecret, SecretName: "chat-cert"}
+			},
+		},
+		{
+			name: "tls from a cluster issuer",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{
+					Mode:      v1alpha1.IngressTLSIssuer,
+					IssuerRef: &v1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+				}
+			},
+		},
+		{
+			name: "issuer mode without issuer",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{Mode: v1alpha1.IngressTLSIssuer}
+			},
+			wantErr: true,
+		},
+		{
+			name: "issuer mode without host",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.Host = ""
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{
+					Mode:      v1alpha1.IngressTLSIssuer,
+					IssuerRef: &v1alpha1.CertificateIssuerReference{Name: "letsencrypt"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown cert-manager issuer kind",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{
+					Mode:      v1alpha1.IngressTLSIssuer,
+					IssuerRef: &v1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "Certificate"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "route reencrypting tls",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{Mode: v1alpha1.IngressTLSSecret, Termination: v1alpha1.RouteTerminationReencrypt}
+			},
+		},
+		{
+			name: "route termination without tls",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{Mode: v1alpha1.IngressTLSNone, Termination: v1alpha1.RouteTerminationEdge}
+			},
+			wantErr: true,
+		},
+		{
+			name: "exposed by an HTTPRoute",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Exposure = &v1alpha1.RocketExposure{
+					Type:    v1alpha1.ExposureGatewayHTTPRoute,
+					Gateway: &v1alpha1.GatewayReference{Name: "public", Namespace: "gateways"},
+				}
+			},
+		},
+		{
+			name: "HTTPRoute without gateway",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Exposure = &v1alpha1.RocketExposure{Type: v1alpha1.ExposureGatewayHTTPRoute}
+			},
+			wantErr: true,
+		},
+		{
+			name: "load balancer with a gateway",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Exposure = &v1alpha1.RocketExposure{
+					Type:    v1alpha1.ExposureLoadBalancer,
+					Gateway: &v1alpha1.GatewayReference{Name: "public"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "issued certificate without ingress",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Exposure = &v1alpha1.RocketExposure{Type: v1alpha1.ExposureNodePort}
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{
+					Mode:      v1alpha1.IngressTLSIssuer,
+					IssuerRef: &v1alpha1.CertificateIssuerReference{Name: "letsencrypt"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "no tls with a secret",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.IngressSpec.TLS = &v1alpha1.RocketIngressTLS{Mode: v1alpha1.IngressTLSNone, SecretName: "chat-cert"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "known settings",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Settings = map[string]string{"Accounts_RegistrationForm": "Disabled", "Accounts_OAuth_Custom-Keycloak": "true"}
+			},
+		},
+		{
+			name:    "unknown setting",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Settings = map[string]string{"Not_A_Setting": "true"} },
+			wantErr: true,
+		},
+		{
+			name:    "setting managed by the operator",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Settings = map[string]string{"Show_Setup_Wizard": "pending"} },
+			wantErr: true,
+		},
+		{
+			name:    "site url derived from the ingress host",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Settings = map[string]string{"Site_Url": "https://other.example.com"} },
+			wantErr: true,
+		},
+		{
+			name:    "setting which isn't an environment variable",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Settings = map[string]string{"Site=Name": "chat"} },
+			wantErr: true,
+		},
+		{
+			name: "settings from a secret",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.SettingsFrom = []v1alpha1.SettingsSource{{SecretRef: &corev1.LocalObjectReference{Name: "oauth"}}}
+			},
+		},
+		{
+			name:    "settings source without reference",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.SettingsFrom = []v1alpha1.SettingsSource{{}} },
+			wantErr: true,
+		},
+		{
+			name: "settings source with secret and config map",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.SettingsFrom = []v1alpha1.SettingsSource{{
+					SecretRef:    &corev1.LocalObjectReference{Name: "oauth"},
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "settings"},
+				}}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rocket := validRocket()
+			tt.mutate(rocket)
+			err := new(RocketValidator).ValidateCreate(context.TODO(), rocket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(r *v1alpha1.Rocket)
+		wantErr bool
+	}{
+		{
+			name: "growing storage",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Database.StorageSpec.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("20Gi")
+			},
+		},
+		{
+			name:   "next database release series",
+			mutate: func(r *v1alpha1.Rocket) { r.Spec.Database.Version = "5.0.3" },
+		},
+		{
+			name:    "skipping a database release series",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Database.Version = "6.0.2" },
+			wantErr: true,
+		},
+		{
+			name:    "downgrading the database release series",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Database.Version = "4.2.17" },
+			wantErr: true,
+		},
+		{
+			name: "switching to an external database",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Database.External = &v1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "atlas"}}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "new version without a backup target",
+			mutate:  func(r *v1alpha1.Rocket) { r.Spec.Version = "3.18.3" },
+			wantErr: true,
+		},
+		{
+			name: "new version with a backup target",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Version = "3.18.3"
+				r.Spec.UpgradeStrategy.BackupTarget = &v1alpha1.BackupTarget{ClaimName: "upgrades"}
+			},
+		},
+		{
+			name: "new version without a backup",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Version = "3.18.3"
+				r.Spec.UpgradeStrategy.SkipBackup = true
+			},
+		},
+		{
+			name: "shrinking storage",
+			mutate: func(r *v1alpha1.Rocket) {
+				r.Spec.Database.StorageSpec.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldRocket := validRocket()
+			rocket := validRocket()
+			tt.mutate(rocket)
+			err := new(RocketValidator).ValidateUpdate(context.TODO(), oldRocket, rocket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateFailedDatabaseUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		failed  bool
+		version string
+		wantErr bool
+	}{
+		{name: "rolling back the failed upgrade", failed: true, version: "4.4.10"},
+		{name: "rolling back to another version of the series", failed: true, version: "4.4.8"},
+		{name: "rolling back past the failed upgrade", failed: true, version: "4.2.17", wantErr: true},
+		{name: "rolling back a completed upgrade", version: "4.4.10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldRocket := validRocket()
+			oldRocket.Spec.Database.Version = "5.0.3"
+			oldRocket.Status.DatabaseVersion = "4.4.10"
+			oldRocket.Status.DatabaseUpgrade = &v1alpha1.DatabaseUpgradeStatus{
+				FromVersion: "4.4.10",
+				ToVersion:   "5.0.3",
+				Phase:       v1alpha1.DatabaseUpgradeCompleted,
+			}
+			if tt.failed {
+				oldRocket.Status.DatabaseUpgrade.Phase = v1alpha1.DatabaseUpgradeFailed
+			}
+			rocket := oldRocket.DeepCopy()
+			rocket.Spec.Database.Version = tt.version
+			err := new(RocketValidator).ValidateUpdate(context.TODO(), oldRocket, rocket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	rocket := &v1alpha1.Rocket{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "default"}}
+	if err := new(RocketDefaulter).Default(context.TODO(), rocket); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if rocket.Spec.Version == "" || rocket.Spec.Database.Version == "" {
+		t.Errorf("Default() didn't set versions: %v, %v", rocket.Spec.Version, rocket.Spec.Database.Version)
+	}
+	if rocket.Spec.Replicas == nil || *rocket.Spec.Replicas != 1 || rocket.Spec.Database.Replicas != 1 {
+		t.Errorf("Default() didn't set replicas: %v, %v", rocket.Spec.Replicas, rocket.Spec.Database.Replicas)
+	}
+	if rocket.Spec.Database.DeletionPolicy != v1alpha1.DeletionPolicyRetain {
+		t.Errorf("Default() didn't set the deletion policy: %v", rocket.Spec.Database.DeletionPolicy)
+	}
+	if rocket.Labels["rocketchat"] != "test" {
+		t.Errorf("Default() didn't set default labels: %v", rocket.Labels)
+	}
+	storage := rocket.Spec.Database.StorageSpec
+	if storage == nil {
+		t.Fatalf("Default() didn't set storageSpec")
+	}
+	if _, ok := storage.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		t.Errorf("Default() didn't set a storage request")
+	}
+
+	rocket.Spec.AdminSpec = &v1alpha1.RocketAdminSpec{Username: "admin", Email: "admin@example.com"}
+	if err := new(RocketValidator).ValidateCreate(context.TODO(), rocket); err != nil {
+		t.Errorf("defaulted rocket is invalid: %v", err)
+	}
+}
+
+func TestDefaultKeepsScaledDownWebserver(t *testing.T) {
+	rocket := validRocket()
+	rocket.Spec.Replicas = util.CreatePointerInt32(0)
+	if err := new(RocketDefaulter).Default(context.TODO(), rocket); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if rocket.Spec.Replicas == nil || *rocket.Spec.Replicas != 0 {
+		t.Errorf("Default() replicas = %v, want 0", rocket.Spec.Replicas)
+	}
+	if err := new(RocketValidator).ValidateCreate(context.TODO(), rocket); err != nil {
+		t.Errorf("scaled down rocket is invalid: %v", err)
+	}
+}
+
+func TestDefaultExternalDatabase(t *testing.T) {
+	rocket := validRocket()
+	rocket.Spec.Database.External = &v1alpha1.ExternalDatabase{SecretRef: corev1.LocalObjectReference{Name: "atlas"}}
+	rocket.Spec.Database.StorageSpec = nil
+	if err := new(RocketDefaulter).Default(context.TODO(), rocket); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if rocket.Spec.Database.StorageSpec != nil {
+		t.Errorf("Default() storageSpec = %v, want none for an external database", rocket.Spec.Database.StorageSpec)
+	}
+}
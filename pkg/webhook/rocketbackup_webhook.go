@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	chatv1alpha1 "github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	backupWebhookLog = ctrl.Log.WithName("webhooks").WithName("RocketBackup")
+
+	rocketBackupGroupKind = schema.GroupKind{Group: chatv1alpha1.SchemeGroupVersion.Group, Kind: "RocketBackup"}
+)
+
+//+kubebuilder:webhook:path=/validate-chat-accso-de-v1alpha1-rocketbackup,mutating=false,failurePolicy=fail,sideEffects=None,groups=chat.accso.de,resources=rocketbackups,verbs=create;update,versions=v1alpha1,name=vrocketbackup.chat.accso.de,admissionReviewVersions=v1
+
+// RocketBackupValidator validates RocketBackup objects on admission,
+// so a backup without a target is refused instead of failing the admission of its job pod
+type RocketBackupValidator struct{}
+
+// SetupRocketBackupWebhookWithManager registers the RocketBackup webhook at the webhook server of the manager
+func SetupRocketBackupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&chatv1alpha1.RocketBackup{}).
+		WithValidator(new(RocketBackupValidator)).
+		Complete()
+}
+
+// ValidateCreate validates the spec of a new RocketBackup
+func (v *RocketBackupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	backup, ok := obj.(*chatv1alpha1.RocketBackup)
+	if !ok {
+		return fmt.Errorf("expected a RocketBackup but got a %T", obj)
+	}
+	backupWebhookLog.V(1).Info("validate create", "object", backup.Name)
+	return toBackupInvalidError(backup, validateRocketBackupSpec(backup))
+}
+
+// ValidateUpdate validates the spec of an updated RocketBackup
+func (v *RocketBackupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	backup, ok := newObj.(*chatv1alpha1.RocketBackup)
+	if !ok {
+		return fmt.Errorf("expected a RocketBackup but got a %T", newObj)
+	}
+	backupWebhookLog.V(1).Info("validate update", "object", backup.Name)
+	return toBackupInvalidError(backup, validateRocketBackupSpec(backup))
+}
+
+// ValidateDelete allows every deletion
+func (v *RocketBackupValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func toBackupInvalidError(backup *chatv1alpha1.RocketBackup, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apiErrors.NewInvalid(rocketBackupGroupKind, backup.Name, allErrs)
+}
+
+func validateRocketBackupSpec(backup *chatv1alpha1.RocketBackup) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if backup.Spec.Rocket == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("rocket"), ""))
+	}
+	return append(allErrs, validateBackupTarget(backup.Spec.Target, specPath.Child("target"))...)
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bachelor-thesis-hown3d/chat-operator/api/chat.accso.de/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRocketBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.RocketBackupSpec
+		wantErr bool
+	}{
+		{
+			name: "claim target",
+			spec: v1alpha1.RocketBackupSpec{Rocket: "test", Target: v1alpha1.BackupTarget{ClaimName: "backups"}},
+		},
+		{
+			name: "s3 target",
+			spec: v1alpha1.RocketBackupSpec{Rocket: "test", Target: v1alpha1.BackupTarget{S3: &v1alpha1.S3Target{
+				Endpoint:          "https://s3.example.com",
+				Bucket:            "backups",
+				CredentialsSecret: corev1.LocalObjectReference{Name: "s3-credentials"},
+			}}},
+		},
+		{
+			name:    "missing target",
+			spec:    v1alpha1.RocketBackupSpec{Rocket: "test"},
+			wantErr: true,
+		},
+		{
+			name:    "missing rocket",
+			spec:    v1alpha1.RocketBackupSpec{Target: v1alpha1.BackupTarget{ClaimName: "backups"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := &v1alpha1.RocketBackup{ObjectMeta: v1.ObjectMeta{Name: "backup", Namespace: "default"}, Spec: tt.spec}
+			validator := new(RocketBackupValidator)
+			if err := validator.ValidateCreate(context.Background(), backup); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := validator.ValidateUpdate(context.Background(), backup, backup); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}